@@ -0,0 +1,404 @@
+package tfidf
+
+import (
+	"math"
+	"sort"
+)
+
+// Structs and types
+
+// Describes how well a corpus covers a set of query terms.
+type CoverageStats struct {
+
+	// Number of query terms considered, repetitions included.
+	Terms int
+	// Number of query terms not present in the corpus.
+	OOVTerms int
+	// Fraction of out-of-vocabulary query terms, OOVTerms / Terms.
+	OOVRate float64
+	// Minimum, maximum and mean idf of all in-vocabulary query terms.
+	MinIDF  float64
+	MaxIDF  float64
+	MeanIDF float64
+}
+
+// Functions
+
+// Counts how often each term occurs in the whole corpus (its
+// collection frequency) and returns these counts together with
+// the total number of tokens in the corpus.
+func collectionFrequencies(documents [][]string) (map[string]float64, float64) {
+
+	frequencies := make(map[string]float64)
+	total := 0.0
+
+	// Range over all documents.
+	for _, document := range documents {
+
+		// Range over all tokens in current document.
+		for _, token := range document {
+			frequencies[token] += 1.0
+			total += 1.0
+		}
+	}
+
+	return frequencies, total
+}
+
+// Compares the log weighted inverse document frequencies of a baseline
+// and a recent corpus and returns the k terms whose idf changed the most
+// in absolute terms, biggest mover first. Terms are taken from both
+// vocabularies, thus emerging terms (only in recent) as well as fading
+// ones (only in baseline) are considered. Equal changes are ordered
+// lexicographically. If k exceeds the number of terms, all are returned.
+func IDFDrift(baseline [][]string, recent [][]string, k int) []string {
+
+	if k <= 0 {
+		return nil
+	}
+
+	baselineIDFs := InverseDocumentFrequencies(baseline, InvDocWeightingLog)
+	recentIDFs := InverseDocumentFrequencies(recent, InvDocWeightingLog)
+
+	// Idf of terms absent from either corpus, without rescanning it.
+	baselineAbsent := weightInverseDocumentFrequency(float64(corpusSize(baseline)), 0.0, 0.0, InvDocWeightingLog)
+	recentAbsent := weightInverseDocumentFrequency(float64(corpusSize(recent)), 0.0, 0.0, InvDocWeightingLog)
+
+	// Absolute idf change per term in the union of both vocabularies.
+	changes := make(map[string]float64)
+
+	for term, idf := range baselineIDFs {
+
+		// Term might not be part of the recent corpus.
+		recentIDF, exists := recentIDFs[term]
+		if !exists {
+			recentIDF = recentAbsent
+		}
+
+		changes[term] = math.Abs(recentIDF - idf)
+	}
+
+	for term, idf := range recentIDFs {
+
+		// Terms in both corpora were handled above.
+		if _, exists := changes[term]; exists {
+			continue
+		}
+
+		changes[term] = math.Abs(idf - baselineAbsent)
+	}
+
+	// Order terms by change, biggest first.
+	terms := make([]string, 0, len(changes))
+	for term := range changes {
+		terms = append(terms, term)
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+
+		if changes[terms[i]] != changes[terms[j]] {
+			return changes[terms[i]] > changes[terms[j]]
+		}
+
+		return terms[i] < terms[j]
+	})
+
+	if k < len(terms) {
+		terms = terms[:k]
+	}
+
+	return terms
+}
+
+// Fits the collection frequency distribution of the corpus to Zipf's
+// law f(r) ~ r^(-s), with r being the frequency rank of a term. The fit
+// is an ordinary least squares regression of log(frequency) on log(rank).
+// It returns the exponent s and the coefficient of determination of the
+// fit, rSquared. Natural language typically yields exponents around 1.0.
+// With less than two distinct terms, both values are 0.0.
+func ZipfFit(documents [][]string) (exponent float64, rSquared float64) {
+
+	frequencies, _ := collectionFrequencies(documents)
+
+	if len(frequencies) < 2 {
+		return 0.0, 0.0
+	}
+
+	// Rank terms by descending collection frequency.
+	counts := make([]float64, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		counts = append(counts, frequency)
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(counts)))
+
+	// Transform to log-log space and compute means.
+	n := float64(len(counts))
+	xs := make([]float64, len(counts))
+	ys := make([]float64, len(counts))
+	meanX, meanY := 0.0, 0.0
+
+	for i, count := range counts {
+		xs[i] = math.Log(float64(i + 1))
+		ys[i] = math.Log(count)
+		meanX += xs[i] / n
+		meanY += ys[i] / n
+	}
+
+	// Least squares estimate of the slope.
+	covariance, varianceX := 0.0, 0.0
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		varianceX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+
+	slope := covariance / varianceX
+	intercept := meanY - slope*meanX
+
+	// Goodness of fit.
+	residual, total := 0.0, 0.0
+	for i := range xs {
+		predicted := intercept + slope*xs[i]
+		residual += (ys[i] - predicted) * (ys[i] - predicted)
+		total += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+
+	// All terms equally frequent, a flat line fits perfectly.
+	if total == 0.0 {
+		return -slope, 1.0
+	}
+
+	return -slope, 1.0 - residual/total
+}
+
+// Reports which fraction of the terms of a single tokenized query are
+// unknown to the corpus and how the idf values of the known ones are
+// distributed. See QuerySetCoverage for the batch variant.
+func QueryCoverage(query []string, documents [][]string, weighting InvDocWeighting) CoverageStats {
+	return QuerySetCoverage([][]string{query}, documents, weighting)
+}
+
+// Aggregates the coverage of a whole set of tokenized queries by the
+// corpus. Terms of all queries are pooled, thus the overall OOV rate is
+// the fraction of all query terms missing from the corpus. Idf statistics
+// are computed over all in-vocabulary terms and are 0.0 if there are none.
+func QuerySetCoverage(queries [][]string, documents [][]string, weighting InvDocWeighting) CoverageStats {
+
+	// Compute idf only once for the whole corpus.
+	idfs := InverseDocumentFrequencies(documents, weighting)
+
+	var stats CoverageStats
+	sumIDF := 0.0
+	known := 0
+
+	// Range over all terms of all queries.
+	for _, query := range queries {
+
+		for _, term := range query {
+
+			stats.Terms++
+
+			idf, exists := idfs[term]
+			if !exists {
+				stats.OOVTerms++
+				continue
+			}
+
+			if known == 0 || idf < stats.MinIDF {
+				stats.MinIDF = idf
+			}
+
+			if known == 0 || idf > stats.MaxIDF {
+				stats.MaxIDF = idf
+			}
+
+			sumIDF += idf
+			known++
+		}
+	}
+
+	if stats.Terms > 0 {
+		stats.OOVRate = float64(stats.OOVTerms) / float64(stats.Terms)
+	}
+
+	if known > 0 {
+		stats.MeanIDF = sumIDF / float64(known)
+	}
+
+	return stats
+}
+
+// Returns the effective vocabulary size of the corpus, i.e. the exponential
+// of the Shannon entropy of its collection frequency distribution. This is
+// the number of equally frequent terms that would yield the same entropy
+// and thus never exceeds the number of distinct terms. An empty corpus
+// has an effective vocabulary size of 0.0.
+func EffectiveVocabularySize(documents [][]string) float64 {
+
+	frequencies, total := collectionFrequencies(documents)
+
+	if total == 0.0 {
+		return 0.0
+	}
+
+	// Shannon entropy of the term distribution.
+	entropy := 0.0
+	for _, frequency := range frequencies {
+		p := frequency / total
+		entropy -= p * math.Log(p)
+	}
+
+	return math.Exp(entropy)
+}
+
+// Measures the leave-one-out influence of each document on the inverse
+// document frequencies of the corpus. The influence of document i is the
+// total absolute idf change over the whole vocabulary that removing it
+// would cause. Terms only occurring in document i vanish with it and
+// contribute their full absolute idf. Instead of recomputing idf N times,
+// document frequencies are counted once and the change caused by removing
+// a document is derived from them, grouping all terms not contained in it
+// by their document frequency.
+func IDFInfluence(documents [][]string, weighting InvDocWeighting) []float64 {
+
+	influences := make([]float64, len(documents))
+	numDocs := float64(corpusSize(documents))
+
+	// Distinct terms per document and document frequency per term.
+	docTerms := make([]map[string]bool, len(documents))
+	frequencies := make(map[string]float64)
+
+	for i, document := range documents {
+
+		docTerms[i] = make(map[string]bool)
+		for _, token := range document {
+
+			if !docTerms[i][token] {
+				docTerms[i][token] = true
+				frequencies[token] += 1.0
+			}
+		}
+	}
+
+	// Most common document frequency and number of terms having it.
+	maxFrequency := maxCount(frequencies)
+	numMax := 0
+	for _, frequency := range frequencies {
+
+		if frequency == maxFrequency {
+			numMax++
+		}
+	}
+
+	// Computes the idf of a term from counts.
+	idf := func(numDocs float64, numDocsWithTerm float64, maxDocsWithTerm float64) float64 {
+		return weightInverseDocumentFrequency(numDocs, numDocsWithTerm, maxDocsWithTerm, weighting)
+	}
+
+	// Removing the only document empties the corpus, all terms vanish.
+	if numDocs == 1.0 {
+
+		for i, document := range documents {
+
+			if document == nil {
+				continue
+			}
+
+			for _, frequency := range frequencies {
+				influences[i] += math.Abs(idf(numDocs, frequency, maxFrequency))
+			}
+		}
+
+		return influences
+	}
+
+	// Change of a term not contained in the removed document only depends
+	// on its document frequency and on the most common document frequency
+	// after removal. The latter either stays or drops by one, depending on
+	// whether the removed document contained all of the most common terms.
+	unaffected := func(frequency float64, maxAfter float64) float64 {
+		return math.Abs(idf(numDocs-1.0, frequency, maxAfter) - idf(numDocs, frequency, maxFrequency))
+	}
+
+	// Sum it up for the whole vocabulary, for both cases.
+	totalStay, totalDrop := 0.0, 0.0
+	for _, frequency := range frequencies {
+		totalStay += unaffected(frequency, maxFrequency)
+		totalDrop += unaffected(frequency, maxFrequency-1.0)
+	}
+
+	for i, document := range documents {
+
+		// Nil documents are not part of the corpus,
+		// removing them changes nothing.
+		if document == nil {
+			continue
+		}
+
+		// Determine most common document frequency after removal.
+		maxInDoc := 0
+		for term := range docTerms[i] {
+
+			if frequencies[term] == maxFrequency {
+				maxInDoc++
+			}
+		}
+
+		maxAfter := maxFrequency
+		influence := totalStay
+
+		if maxInDoc == numMax {
+			maxAfter = maxFrequency - 1.0
+			influence = totalDrop
+		}
+
+		// Correct the change of all terms of the removed document.
+		for term := range docTerms[i] {
+
+			frequency := frequencies[term]
+			influence -= unaffected(frequency, maxAfter)
+
+			if frequency > 1.0 {
+				influence += math.Abs(idf(numDocs-1.0, frequency-1.0, maxAfter) - idf(numDocs, frequency, maxFrequency))
+			} else {
+				influence += math.Abs(idf(numDocs, frequency, maxFrequency))
+			}
+		}
+
+		influences[i] = influence
+	}
+
+	return influences
+}
+
+// Computes the Shannon entropy (in nats) of the distribution of an
+// already tokenized term across the documents of the corpus, an
+// information-theoretic alternative to idf. The probability of document
+// d is the frequency of term in d divided by the frequency of term in the
+// whole corpus. Terms concentrated in few documents have a low entropy
+// and are more distinctive, a term spread evenly over k documents reaches
+// the maximum of log(k). Terms occurring in at most one document have an
+// entropy of 0.0, as have terms absent from the corpus.
+func TermEntropy(term string, documents [][]string) float64 {
+
+	// Frequency of term per document and in the corpus.
+	frequencies := make([]float64, 0, len(documents))
+	total := 0.0
+
+	for _, document := range documents {
+
+		if frequency := TermFrequency(term, false, document, TermWeightingRaw); frequency > 0.0 {
+			frequencies = append(frequencies, frequency)
+			total += frequency
+		}
+	}
+
+	// Shannon entropy of the term's distribution.
+	entropy := 0.0
+	for _, frequency := range frequencies {
+		p := frequency / total
+		entropy -= p * math.Log(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,203 @@
+package tfidf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTermEntropy(t *testing.T) {
+
+	documents := [][]string{
+		{"spread", "skewed", "skewed", "skewed", "rare", "rare"},
+		{"spread", "skewed"},
+		{"spread"},
+		{"spread", "other"},
+	}
+
+	tests := []struct {
+		term string
+		want float64
+	}{
+		{"spread", math.Log(4.0)},
+		{"skewed", -(0.75*math.Log(0.75) + 0.25*math.Log(0.25))},
+		{"rare", 0.0},
+		{"absent", 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := TermEntropy(test.term, documents); !almostEqual(got, test.want) {
+			t.Errorf("TermEntropy(%s) = %v, want %v", test.term, got, test.want)
+		}
+	}
+
+	// Evenly spread terms are less distinctive than concentrated ones.
+	if spread, skewed := TermEntropy("spread", documents), TermEntropy("skewed", documents); spread <= skewed {
+		t.Errorf("entropy of uniform term %v not above concentrated term %v", spread, skewed)
+	}
+
+	if got := TermEntropy("spread", nil); got != 0.0 {
+		t.Errorf("TermEntropy on empty corpus = %v, want 0", got)
+	}
+}
+
+func TestIDFDrift(t *testing.T) {
+
+	// Baseline idfs: a = log(4 / 3), b = log(4), c = log(2).
+	baseline := [][]string{
+		{"a", "b"},
+		{"a"},
+		{"c"},
+		{"a", "c"},
+	}
+
+	// Recent idfs: a = log(4), b = log(4 / 3), d = log(4).
+	recent := [][]string{
+		{"a"},
+		{"b"},
+		{"b", "d"},
+		{"b"},
+	}
+
+	// "a" rises and "b" falls by log(3), which ranks them the same. The
+	// emerging "d" changes by log(4), the fading "c" by log(2).
+	tests := []struct {
+		k    int
+		want []string
+	}{
+		{1, []string{"d"}},
+		{3, []string{"d", "a", "b"}},
+		{4, []string{"d", "a", "b", "c"}},
+		{10, []string{"d", "a", "b", "c"}},
+		{0, nil},
+		{-1, nil},
+	}
+
+	for _, test := range tests {
+
+		if got := IDFDrift(baseline, recent, test.k); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("IDFDrift(%d) = %q, want %q", test.k, got, test.want)
+		}
+	}
+
+	// The direction of the drift does not matter.
+	if got, want := IDFDrift(recent, baseline, 4), []string{"d", "a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDFDrift of swapped corpora = %q, want %q", got, want)
+	}
+
+	// Without any change, terms are ordered lexicographically.
+	if got, want := IDFDrift(baseline, baseline, 10), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDFDrift of same corpus = %q, want %q", got, want)
+	}
+}
+
+func TestZipfFit(t *testing.T) {
+
+	// Builds a single document in which the term of rank r
+	// occurs scale * r^(-exponent) times (rounded down).
+	zipfCorpus := func(exponent float64, scale float64, ranks int) [][]string {
+
+		document := make([]string, 0)
+		for r := 1; r <= ranks; r++ {
+
+			for i := 0; i < int(scale*math.Pow(float64(r), -exponent)); i++ {
+				document = append(document, fmt.Sprintf("term%d", r))
+			}
+		}
+
+		return [][]string{document}
+	}
+
+	for _, generating := range []float64{0.8, 1.0, 1.5} {
+
+		exponent, rSquared := ZipfFit(zipfCorpus(generating, 5000.0, 40))
+
+		if math.Abs(exponent-generating) > 0.02 {
+			t.Errorf("exponent of Zipf corpus with %v = %v", generating, exponent)
+		}
+
+		if rSquared < 0.999 || rSquared > 1.0 {
+			t.Errorf("r² of Zipf corpus with %v = %v, want close to 1", generating, rSquared)
+		}
+	}
+
+	// Two ranks always fit exactly: 8 = 2 * 2^2.
+	if exponent, rSquared := ZipfFit([][]string{{"a", "a", "a", "a", "a", "a", "a", "a"}, {"b", "b"}}); !almostEqual(exponent, 2.0) || !almostEqual(rSquared, 1.0) {
+		t.Errorf("ZipfFit of two ranks = %v, %v, want 2, 1", exponent, rSquared)
+	}
+
+	// Equally frequent terms lie on a flat line.
+	if exponent, rSquared := ZipfFit([][]string{{"a", "b", "c"}}); exponent != 0.0 || rSquared != 1.0 {
+		t.Errorf("ZipfFit of equally frequent terms = %v, %v, want 0, 1", exponent, rSquared)
+	}
+
+	for _, documents := range [][][]string{nil, {{}}, {{"a", "a"}, {"a"}}} {
+
+		if exponent, rSquared := ZipfFit(documents); exponent != 0.0 || rSquared != 0.0 {
+			t.Errorf("ZipfFit(%q) = %v, %v, want 0, 0", documents, exponent, rSquared)
+		}
+	}
+}
+
+func TestQuerySetCoverage(t *testing.T) {
+
+	// Idfs: a = log(4 / 3), b = c = log(4).
+	documents := [][]string{
+		{"a", "b"},
+		{"a"},
+		{"c"},
+		{"a"},
+	}
+
+	common, rare := math.Log(4.0/3.0), math.Log(4.0)
+
+	first := []string{"a", "b", "x"}
+	second := []string{"c", "c", "y", "z"}
+
+	tests := []struct {
+		name  string
+		stats CoverageStats
+		want  CoverageStats
+	}{
+		{"single query", QueryCoverage(first, documents, InvDocWeightingLog), CoverageStats{3, 1, 1.0 / 3.0, common, rare, (common + rare) / 2.0}},
+		{"repeated terms", QueryCoverage(second, documents, InvDocWeightingLog), CoverageStats{4, 2, 0.5, rare, rare, rare}},
+		{"pooled queries", QuerySetCoverage([][]string{first, second}, documents, InvDocWeightingLog), CoverageStats{7, 3, 3.0 / 7.0, common, rare, (common + 3.0*rare) / 4.0}},
+		{"unknown terms only", QueryCoverage([]string{"x", "y"}, documents, InvDocWeightingLog), CoverageStats{2, 2, 1.0, 0.0, 0.0, 0.0}},
+		{"no queries", QuerySetCoverage(nil, documents, InvDocWeightingLog), CoverageStats{}},
+		{"empty corpus", QueryCoverage(first, nil, InvDocWeightingLog), CoverageStats{3, 3, 1.0, 0.0, 0.0, 0.0}},
+	}
+
+	for _, test := range tests {
+
+		got, want := test.stats, test.want
+		if got.Terms != want.Terms || got.OOVTerms != want.OOVTerms || !almostEqual(got.OOVRate, want.OOVRate) ||
+			!almostEqual(got.MinIDF, want.MinIDF) || !almostEqual(got.MaxIDF, want.MaxIDF) || !almostEqual(got.MeanIDF, want.MeanIDF) {
+			t.Errorf("%s: coverage = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
+
+func TestEffectiveVocabularySize(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		documents [][]string
+		want      float64
+	}{
+		// Entropy of (1/2, 1/4, 1/4) is 3/2 * log(2).
+		{"skewed", [][]string{{"a", "a"}, {"b", "c"}}, 2.0 * math.Sqrt(2.0)},
+		{"uniform", [][]string{{"a", "b"}, {"c"}}, 3.0},
+		{"single term", [][]string{{"a", "a"}, {"a"}}, 1.0},
+		{"empty documents", [][]string{{}, nil}, 0.0},
+		{"empty corpus", nil, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := EffectiveVocabularySize(test.documents); !almostEqual(got, test.want) {
+			t.Errorf("%s: EffectiveVocabularySize = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
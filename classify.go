@@ -0,0 +1,94 @@
+package tfidf
+
+import (
+	"sort"
+)
+
+// Structs and types
+
+// One labeled document and its similarity to the
+// document that is about to be classified.
+type neighbor struct {
+	index      int
+	similarity float64
+}
+
+// Functions
+
+// Classifies the tokenized doc by the majority label among its k
+// nearest neighbors in labeledDocs, where nearness is the cosine
+// similarity of tf-idf vectors computed against the labeled corpus.
+// labels[i] holds the label of labeledDocs[i]. Neighbors with equal
+// similarity are taken in corpus order and tied votes go to the
+// lexicographically smallest label. If k exceeds the number of labeled
+// documents, all of them vote. An empty string is returned if k <= 0,
+// no labeled documents are supplied or labels and documents mismatch.
+func ClassifyKNN(doc []string, labeledDocs [][]string, labels []string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting) string {
+	return classifyKNN(doc, labeledDocs, labels, k, tfWeighting, idfWeighting, false)
+}
+
+// Works like ClassifyKNN but each of the k nearest neighbors votes
+// with its cosine similarity instead of a single count, so closer
+// documents have a bigger say in the resulting label.
+func ClassifyKNNWeighted(doc []string, labeledDocs [][]string, labels []string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting) string {
+	return classifyKNN(doc, labeledDocs, labels, k, tfWeighting, idfWeighting, true)
+}
+
+// Shared implementation of the kNN classifiers. If weighted is
+// set to true, votes carry the neighbor's similarity.
+func classifyKNN(doc []string, labeledDocs [][]string, labels []string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting, weighted bool) string {
+
+	if k <= 0 || len(labeledDocs) == 0 || len(labeledDocs) != len(labels) {
+		return ""
+	}
+
+	if k > len(labeledDocs) {
+		k = len(labeledDocs)
+	}
+
+	// Vectorize the document and the labeled corpus
+	// based on the idf of the labeled corpus.
+	idfs := InverseDocumentFrequencies(labeledDocs, idfWeighting)
+	docVector := tfIdfVector(doc, idfs, tfWeighting)
+
+	neighbors := make([]neighbor, len(labeledDocs))
+	for i, labeledDoc := range labeledDocs {
+		neighbors[i] = neighbor{
+			index:      i,
+			similarity: CosineSimilarity(docVector, tfIdfVector(labeledDoc, idfs, tfWeighting)),
+		}
+	}
+
+	// Order by similarity, keeping corpus order among equals.
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		return neighbors[i].similarity > neighbors[j].similarity
+	})
+
+	// Let the k nearest neighbors vote.
+	votes := make(map[string]float64)
+	for _, n := range neighbors[:k] {
+
+		if weighted {
+			votes[labels[n.index]] += n.similarity
+		} else {
+			votes[labels[n.index]] += 1.0
+		}
+	}
+
+	// Pick the label with most votes, breaking
+	// ties by lexicographical order.
+	winner := ""
+	winnerVotes := 0.0
+	first := true
+
+	for label, count := range votes {
+
+		if first || count > winnerVotes || (count == winnerVotes && label < winner) {
+			winner = label
+			winnerVotes = count
+			first = false
+		}
+	}
+
+	return winner
+}
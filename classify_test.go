@@ -0,0 +1,51 @@
+package tfidf
+
+import (
+	"testing"
+)
+
+func TestClassifyKNN(t *testing.T) {
+
+	doc := []string{"a", "b"}
+
+	// With binary tf and unary idf, the cosine similarities to doc
+	// are 1, 1/2, 1/sqrt(8) and 0 in corpus order.
+	labeledDocs := [][]string{
+		{"a", "b"},
+		{"a", "c"},
+		{"a", "c", "d", "e"},
+		{"f"},
+	}
+
+	tests := []struct {
+		name         string
+		labels       []string
+		k            int
+		want         string
+		wantWeighted string
+	}{
+		{"nearest neighbor", []string{"x", "y", "y", "z"}, 1, "x", "x"},
+		{"majority against similarity", []string{"x", "y", "y", "z"}, 3, "y", "x"},
+		{"tie goes to smallest label", []string{"y", "x", "x", "z"}, 2, "x", "y"},
+		{"all documents vote", []string{"x", "y", "y", "z"}, 4, "y", "x"},
+		{"k exceeds documents", []string{"x", "y", "y", "z"}, 10, "y", "x"},
+		{"zero k", []string{"x", "y", "y", "z"}, 0, "", ""},
+		{"negative k", []string{"x", "y", "y", "z"}, -1, "", ""},
+		{"mismatched labels", []string{"x", "y"}, 2, "", ""},
+	}
+
+	for _, test := range tests {
+
+		if got := ClassifyKNN(doc, labeledDocs, test.labels, test.k, TermWeightingBinary, InvDocWeightingUnary); got != test.want {
+			t.Errorf("%s: ClassifyKNN = %q, want %q", test.name, got, test.want)
+		}
+
+		if got := ClassifyKNNWeighted(doc, labeledDocs, test.labels, test.k, TermWeightingBinary, InvDocWeightingUnary); got != test.wantWeighted {
+			t.Errorf("%s: ClassifyKNNWeighted = %q, want %q", test.name, got, test.wantWeighted)
+		}
+	}
+
+	if got := ClassifyKNN(doc, nil, nil, 1, TermWeightingBinary, InvDocWeightingUnary); got != "" {
+		t.Errorf("ClassifyKNN without labeled documents = %q, want empty string", got)
+	}
+}
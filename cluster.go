@@ -0,0 +1,152 @@
+package tfidf
+
+import (
+	"math/rand"
+)
+
+// Constants
+
+const (
+
+	// Default upper bound of Lloyd iterations for KMeansCluster.
+	KMeansMaxIterations int = 100
+	// Default seed used to pick the initial centroids.
+	KMeansSeed int64 = 1
+)
+
+// Functions
+
+// Clusters the supplied corpus of tokenized documents into k groups
+// using cosine-based k-means over the documents' tf-idf vectors.
+// It returns the cluster index (0 to k-1) for each document and
+// uses the default iteration cap and seed, see KMeansClusterWithOptions.
+func KMeansCluster(documents [][]string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []int {
+	return KMeansClusterWithOptions(documents, k, tfWeighting, idfWeighting, KMeansMaxIterations, KMeansSeed)
+}
+
+// Runs Lloyd's algorithm on the L2 normalized tf-idf vectors of all
+// documents, assigning each document to the centroid with the highest
+// cosine similarity. Initial centroids are k distinct documents picked
+// by a random source seeded with seed, which makes the result deterministic.
+// Documents without any weighted term are only picked as initial centroids
+// if fewer than k other documents exist.
+// Iteration stops as soon as no assignment changes or after maxIterations
+// rounds. If k exceeds the number of documents, it is lowered to it.
+// For k <= 0 or an empty corpus, nil is returned.
+func KMeansClusterWithOptions(documents [][]string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting, maxIterations int, seed int64) []int {
+
+	if k <= 0 || len(documents) == 0 {
+		return nil
+	}
+
+	if k > len(documents) {
+		k = len(documents)
+	}
+
+	// Compute and normalize tf-idf vectors of all documents.
+	vectors := tfIdfVectors(documents, tfWeighting, idfWeighting)
+	for i := range vectors {
+		NormalizeInPlace(vectors[i])
+	}
+
+	// Pick k distinct documents as initial centroids. Documents without
+	// any weighted term are similar to nothing and would attract all
+	// ties, thus they are only picked if too few others are left.
+	random := rand.New(rand.NewSource(seed))
+	order := random.Perm(len(vectors))
+
+	candidates := make([]int, 0, len(vectors))
+	for _, doc := range order {
+
+		if vectorNorm(vectors[doc]) > 0.0 {
+			candidates = append(candidates, doc)
+		}
+	}
+	for _, doc := range order {
+
+		if vectorNorm(vectors[doc]) == 0.0 {
+			candidates = append(candidates, doc)
+		}
+	}
+
+	centroids := make([]map[string]float64, k)
+	for i, doc := range candidates[:k] {
+		centroids[i] = vectors[doc]
+	}
+
+	// Mark all documents as unassigned initially.
+	assignments := make([]int, len(vectors))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+
+		changed := false
+
+		// Assignment step: move each document to its most
+		// similar centroid. Ties go to the lower cluster index.
+		for i, vector := range vectors {
+
+			best := 0
+			bestSimilarity := CosineSimilarity(vector, centroids[0])
+
+			for j := 1; j < k; j++ {
+
+				if similarity := CosineSimilarity(vector, centroids[j]); similarity > bestSimilarity {
+					best = j
+					bestSimilarity = similarity
+				}
+			}
+
+			if assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+
+		// Converged, nothing moved.
+		if !changed {
+			break
+		}
+
+		// Update step: recompute each centroid as the mean of its members.
+		// Clusters that ran empty keep their previous centroid.
+		members := make([][]map[string]float64, k)
+		for i, cluster := range assignments {
+			members[cluster] = append(members[cluster], vectors[i])
+		}
+
+		for j := range centroids {
+
+			if len(members[j]) > 0 {
+				centroids[j] = centroid(members[j])
+			}
+		}
+	}
+
+	return assignments
+}
+
+// Scores each document of the corpus by how much its tf-idf vector
+// deviates from the corpus centroid, which is the mean of all L2
+// normalized document vectors. The score is the cosine distance
+// 1 - cos(document, centroid), thus higher scores mark outliers.
+// Documents without any weighted term receive the maximum score of 1.0.
+func AnomalyScores(documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []float64 {
+
+	// Compute and normalize tf-idf vectors of all documents.
+	vectors := tfIdfVectors(documents, tfWeighting, idfWeighting)
+	for i := range vectors {
+		NormalizeInPlace(vectors[i])
+	}
+
+	mean := centroid(vectors)
+
+	scores := make([]float64, len(vectors))
+	for i, vector := range vectors {
+		scores[i] = 1.0 - CosineSimilarity(vector, mean)
+	}
+
+	return scores
+}
@@ -0,0 +1,58 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKMeansClusterSkipsZeroVectorSeeds(t *testing.T) {
+
+	// Term "a" occurs in every document and weighs 0.0 with log idf,
+	// thus document 1 is a zero vector while 0 and 2 are orthogonal.
+	documents := [][]string{{"a", "b"}, {"a"}, {"a", "c"}}
+
+	for seed := int64(0); seed < 20; seed++ {
+
+		assignments := KMeansClusterWithOptions(documents, 2, TermWeightingRaw, InvDocWeightingLog, KMeansMaxIterations, seed)
+		if assignments[0] == assignments[2] {
+			t.Fatalf("seed %d: orthogonal documents share a cluster: %v", seed, assignments)
+		}
+	}
+}
+
+func TestAnomalyScores(t *testing.T) {
+
+	// With raw tf and unary idf, the normalized vectors are the unit
+	// vectors of a and b, their centroid is (1/2, 1/4) with a length
+	// of sqrt(5) / 4.
+	documents := [][]string{
+		{"a"},
+		{"a", "a"},
+		{"b"},
+		{},
+	}
+
+	want := []float64{
+		1.0 - 2.0/math.Sqrt(5.0),
+		1.0 - 2.0/math.Sqrt(5.0),
+		1.0 - 1.0/math.Sqrt(5.0),
+		1.0,
+	}
+
+	scores := AnomalyScores(documents, TermWeightingRaw, InvDocWeightingUnary)
+	if len(scores) != len(want) {
+		t.Fatalf("got %d scores for %d documents", len(scores), len(want))
+	}
+
+	for i := range want {
+
+		if !almostEqual(scores[i], want[i]) {
+			t.Errorf("score of document %d = %v, want %v", i, scores[i], want[i])
+		}
+	}
+
+	// The outlier scores highest among documents with terms.
+	if scores[2] <= scores[0] {
+		t.Errorf("outlier score %v not above %v", scores[2], scores[0])
+	}
+}
@@ -0,0 +1,213 @@
+package tfidf
+
+import (
+	"io"
+	"sort"
+)
+
+// Structs and types
+
+// A corpus of tokenized documents that is ingested once and answers
+// inverse document frequency queries from cached statistics instead of
+// rescanning all documents. It keeps an inverted index from each term
+// to the set of documents containing it. Adding documents updates the
+// index incrementally, the idf cache is rebuilt lazily on the next
+// query. Optionally, rare terms are pruned periodically to keep the
+// index bounded, see EnablePruning. A Corpus is not safe for concurrent use.
+type Corpus struct {
+	weighting InvDocWeighting
+	numDocs   int
+	index     map[string]map[int]bool
+	idfs      map[string]float64
+	maxDocs   float64
+	firstSeen map[string]int
+	minDocs   int
+	grace     int
+}
+
+// Functions
+
+// Creates a new Corpus from the supplied tokenized documents whose
+// inverse document frequencies will be weighted by weighting.
+// Documents receive IDs in the order they are supplied, starting at 0.
+// Nil documents are skipped and receive no ID, see AddDocument.
+func NewCorpus(documents [][]string, weighting InvDocWeighting) *Corpus {
+
+	c := &Corpus{
+		weighting: weighting,
+		index:     make(map[string]map[int]bool),
+		firstSeen: make(map[string]int),
+	}
+
+	for _, document := range documents {
+		c.AddDocument(document)
+	}
+
+	return c
+}
+
+// Adds a tokenized document to the corpus and returns its ID. A nil
+// document is not added and -1 is returned, see ValidateDocuments.
+func (c *Corpus) AddDocument(document []string) int {
+
+	if document == nil {
+		return -1
+	}
+
+	id := c.numDocs
+	c.numDocs++
+
+	// Record document in the inverted index of all its terms.
+	for _, token := range document {
+
+		if c.index[token] == nil {
+			c.index[token] = make(map[int]bool)
+			c.firstSeen[token] = id
+		}
+		c.index[token][id] = true
+	}
+
+	// Corpus size changed, thus all idf values did.
+	c.idfs = nil
+
+	// Prune rare terms once per grace period.
+	if c.grace > 0 && (c.numDocs%c.grace) == 0 {
+		c.prune()
+	}
+
+	return id
+}
+
+// Enables periodic pruning of rare terms, e.g. one-off typos, for long
+// running ingestion. Every gracePeriod added documents, all terms that
+// were first seen at least gracePeriod documents ago and still occur in
+// less than minDocumentFrequency documents are removed from the corpus.
+// Pruned terms are treated like terms unknown to the corpus from then
+// on, if they occur again they start over. Documents containing them
+// still count towards the number of documents. A gracePeriod <= 0
+// disables pruning again.
+func (c *Corpus) EnablePruning(minDocumentFrequency int, gracePeriod int) {
+	c.minDocs = minDocumentFrequency
+	c.grace = gracePeriod
+}
+
+// Reads one raw document from r, tokenizes it with TokenizeDocument
+// and adds it to the corpus, returning its ID. Only the inverted index
+// is updated, neither the raw nor the tokenized document is kept, which
+// allows to stream large corpora document by document. On read errors
+// the corpus is left unchanged.
+func (c *Corpus) ReadDocument(r io.Reader) (int, error) {
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return -1, err
+	}
+
+	return c.AddDocument(TokenizeDocument(string(raw))), nil
+}
+
+// Returns the inverse document frequencies of all terms in the corpus,
+// e.g. once streaming in documents is finished. The returned map is a
+// copy and not affected by documents added later on.
+func (c *Corpus) InverseDocumentFrequencies() map[string]float64 {
+
+	cached := c.inverseDocumentFrequencies()
+
+	idfs := make(map[string]float64, len(cached))
+	for term, idf := range cached {
+		idfs[term] = idf
+	}
+
+	return idfs
+}
+
+// Returns the number of documents in the corpus.
+func (c *Corpus) NumDocuments() int {
+	return c.numDocs
+}
+
+// Returns the number of documents in the corpus containing term.
+func (c *Corpus) DocumentFrequency(term string) int {
+	return len(c.index[term])
+}
+
+// Returns the inverse document frequency of an already tokenized
+// term in the corpus. Answered from cache in O(1) unless documents
+// were added since the last query.
+func (c *Corpus) IDF(term string) float64 {
+
+	if idf, exists := c.inverseDocumentFrequencies()[term]; exists {
+		return idf
+	}
+
+	// Term is unknown to the corpus.
+	return weightInverseDocumentFrequency(float64(c.numDocs), 0.0, c.maxDocs, c.weighting)
+}
+
+// Returns the sorted list of all distinct terms in the corpus.
+func (c *Corpus) Vocabulary() []string {
+
+	vocabulary := make([]string, 0, len(c.index))
+	for term := range c.index {
+		vocabulary = append(vocabulary, term)
+	}
+
+	sort.Strings(vocabulary)
+
+	return vocabulary
+}
+
+// Returns the cached idf map, rebuilding it if it is stale.
+func (c *Corpus) inverseDocumentFrequencies() map[string]float64 {
+
+	if c.idfs != nil {
+		return c.idfs
+	}
+
+	c.maxDocs = c.maxDocumentFrequency()
+
+	c.idfs = make(map[string]float64, len(c.index))
+	for term, docs := range c.index {
+		c.idfs[term] = weightInverseDocumentFrequency(float64(c.numDocs), float64(len(docs)), c.maxDocs, c.weighting)
+	}
+
+	return c.idfs
+}
+
+// Removes all terms from the index that had their grace period to
+// reach the minimum document frequency but did not make it.
+func (c *Corpus) prune() {
+
+	pruned := false
+
+	for term, docs := range c.index {
+
+		if len(docs) < c.minDocs && (c.numDocs-c.firstSeen[term]) >= c.grace {
+			delete(c.index, term)
+			delete(c.firstSeen, term)
+			pruned = true
+		}
+	}
+
+	// Vocabulary changed, thus the most common
+	// term and all idf values may have.
+	if pruned {
+		c.idfs = nil
+		c.maxDocs = 0.0
+	}
+}
+
+// Returns the document frequency of the most common term.
+func (c *Corpus) maxDocumentFrequency() float64 {
+
+	highest := 0
+
+	for _, docs := range c.index {
+
+		if len(docs) > highest {
+			highest = len(docs)
+		}
+	}
+
+	return float64(highest)
+}
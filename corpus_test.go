@@ -0,0 +1,167 @@
+package tfidf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCorpusPruning(t *testing.T) {
+
+	c := NewCorpus(nil, InvDocWeightingLog)
+	c.EnablePruning(2, 2)
+
+	c.AddDocument([]string{"common", "typo"})
+	c.AddDocument([]string{"common"})
+
+	// After the first grace period, the one-off term is gone.
+	if got, want := c.Vocabulary(), []string{"common"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("vocabulary after pruning = %v, want %v", got, want)
+	}
+
+	if got := c.DocumentFrequency("typo"); got != 0 {
+		t.Errorf("DocumentFrequency(typo) = %d, want 0", got)
+	}
+
+	// A term first seen within the current grace period is kept.
+	c.AddDocument([]string{"common"})
+	c.AddDocument([]string{"fresh"})
+
+	if got, want := c.Vocabulary(), []string{"common", "fresh"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("vocabulary = %v, want %v", got, want)
+	}
+
+	// Pruned documents still count towards the corpus size.
+	if got := c.NumDocuments(); got != 4 {
+		t.Errorf("NumDocuments() = %d, want 4", got)
+	}
+}
+
+func TestCorpusPruningInvalidatesCache(t *testing.T) {
+
+	c := NewCorpus(nil, InvDocWeightingLog)
+	c.EnablePruning(2, 3)
+
+	c.AddDocument([]string{"a", "rare"})
+	c.AddDocument([]string{"a"})
+
+	// Fill the cache before pruning happens.
+	if got := c.IDF("rare"); got <= 0.0 {
+		t.Fatalf("IDF(rare) before pruning = %v, want > 0", got)
+	}
+
+	c.AddDocument([]string{"a"})
+
+	if _, exists := c.InverseDocumentFrequencies()["rare"]; exists {
+		t.Errorf("pruned term still in cached idf map")
+	}
+
+	if got := c.IDF("rare"); got != 0.0 {
+		t.Errorf("IDF(rare) after pruning = %v, want 0", got)
+	}
+}
+
+func TestCorpusMatchesInverseDocumentFrequency(t *testing.T) {
+
+	documents := syntheticCorpus(30, 20, 80)
+	documents[3] = nil
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		c := NewCorpus(documents, weighting)
+
+		for _, term := range append(Vocabulary(documents), "absent") {
+
+			if got, want := c.IDF(term), InverseDocumentFrequency(term, false, documents, weighting); got != want {
+				t.Errorf("weighting %d: IDF(%s) = %v, want %v", weighting, term, got, want)
+			}
+		}
+	}
+}
+
+// Looks up the idf of every term of the vocabulary
+// from a Corpus built once up front.
+func BenchmarkCorpusIDF(b *testing.B) {
+
+	documents := syntheticCorpus(1000, 100, 5000)
+	vocabulary := Vocabulary(documents)
+	c := NewCorpus(documents, InvDocWeightingLog)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+
+		for _, term := range vocabulary {
+			c.IDF(term)
+		}
+	}
+}
+
+// Looks up the idf of the same terms as BenchmarkCorpusIDF,
+// but rescans the corpus for every one of them.
+func BenchmarkInverseDocumentFrequency(b *testing.B) {
+
+	documents := syntheticCorpus(1000, 100, 5000)
+	vocabulary := Vocabulary(documents)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+
+		for _, term := range vocabulary {
+			InverseDocumentFrequency(term, false, documents, InvDocWeightingLog)
+		}
+	}
+}
+
+// Computes the idf of the same terms as BenchmarkInverseDocumentFrequency,
+// but counts document frequencies in a single pass over the corpus.
+func BenchmarkInverseDocumentFrequencies(b *testing.B) {
+
+	documents := syntheticCorpus(1000, 100, 5000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		InverseDocumentFrequencies(documents, InvDocWeightingLog)
+	}
+}
+
+func TestCorpusReadDocument(t *testing.T) {
+
+	raw := []string{
+		"The runners were running through the park",
+		"A runner ran home",
+		"Dogs bark in the park",
+	}
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		c := NewCorpus(nil, weighting)
+
+		for i, document := range raw {
+
+			id, err := c.ReadDocument(strings.NewReader(document))
+			if err != nil || id != i {
+				t.Fatalf("ReadDocument(%q) = %d, %v, want %d, nil", document, id, err, i)
+			}
+		}
+
+		if got, want := c.InverseDocumentFrequencies(), InverseDocumentFrequenciesStrings(raw, weighting); !reflect.DeepEqual(got, want) {
+			t.Errorf("weighting %d: streamed idfs = %v, want %v", weighting, got, want)
+		}
+	}
+}
+
+func TestCorpusReadDocumentError(t *testing.T) {
+
+	c := NewCorpus([][]string{{"park"}}, InvDocWeightingLog)
+	failure := errors.New("connection reset")
+
+	if id, err := c.ReadDocument(iotest.ErrReader(failure)); id != -1 || !errors.Is(err, failure) {
+		t.Errorf("ReadDocument = %d, %v, want -1, %v", id, err, failure)
+	}
+
+	if got := c.NumDocuments(); got != 1 {
+		t.Errorf("NumDocuments after failed read = %d, want 1", got)
+	}
+}
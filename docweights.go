@@ -0,0 +1,77 @@
+package tfidf
+
+import (
+	"math"
+	"time"
+)
+
+// Functions
+
+// Returns the weight of document i from the supplied weights.
+// Documents without a weight count with a weight of 1.0.
+func documentWeight(weights []float64, i int) float64 {
+
+	if i < len(weights) {
+		return weights[i]
+	}
+
+	return 1.0
+}
+
+// Derives per-document weights from caller supplied cluster labels,
+// labels[i] being the cluster of document i. Each document is weighted
+// inversely to the size of its cluster, so that every cluster contributes
+// the same total weight. Weights are scaled to sum up to the number of
+// documents, keeping the corpus size unchanged. Pass the result to the
+// weighted inverse document frequency functions to keep a few big
+// topical clusters from dominating idf.
+func ClusterBalancedWeights(labels []string) []float64 {
+
+	// Count the size of each cluster.
+	sizes := make(map[string]int)
+	for _, label := range labels {
+		sizes[label]++
+	}
+
+	weights := make([]float64, len(labels))
+
+	for i, label := range labels {
+		weights[i] = float64(len(labels)) / (float64(len(sizes)) * float64(sizes[label]))
+	}
+
+	return weights
+}
+
+// Converts per-document timestamps into ages relative to now,
+// expressed in multiples of unit (e.g. time.Hour or 24 * time.Hour).
+// Timestamps after now result in negative ages. A unit <= 0 falls
+// back to nanoseconds, the base unit of time.Duration.
+func DocumentAges(timestamps []time.Time, now time.Time, unit time.Duration) []float64 {
+
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+
+	ages := make([]float64, len(timestamps))
+
+	for i, timestamp := range timestamps {
+		ages[i] = float64(now.Sub(timestamp)) / float64(unit)
+	}
+
+	return ages
+}
+
+// Derives exponentially decaying per-document weights exp(-lambda * age)
+// from the supplied document ages. lambda is the decay rate per unit of
+// age, a lambda of 0.0 weights all documents with 1.0. Negative ages,
+// i.e. documents from the future, weigh more than 1.0.
+func TimeDecayWeights(ages []float64, lambda float64) []float64 {
+
+	weights := make([]float64, len(ages))
+
+	for i, age := range ages {
+		weights[i] = math.Exp(-lambda * age)
+	}
+
+	return weights
+}
@@ -0,0 +1,174 @@
+package tfidf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClusterBalancedWeights(t *testing.T) {
+
+	labels := []string{"a", "b", "a", "a", "c", "b"}
+	weights := ClusterBalancedWeights(labels)
+
+	if len(weights) != len(labels) {
+		t.Fatalf("got %d weights for %d labels", len(weights), len(labels))
+	}
+
+	// Each of three clusters sums up to 6 / 3, all of them to 6.
+	sums := make(map[string]float64)
+	total := 0.0
+	for i, label := range labels {
+		sums[label] += weights[i]
+		total += weights[i]
+	}
+
+	for label, sum := range sums {
+
+		if !almostEqual(sum, 2.0) {
+			t.Errorf("weights of cluster %s sum up to %v, want 2", label, sum)
+		}
+	}
+
+	if !almostEqual(total, 6.0) {
+		t.Errorf("weights sum up to %v, want 6", total)
+	}
+
+	if !almostEqual(weights[0], 2.0/3.0) || !almostEqual(weights[4], 2.0) {
+		t.Errorf("weights = %v, want 2/3 for cluster a and 2 for cluster c", weights)
+	}
+
+	if got := ClusterBalancedWeights(nil); len(got) != 0 {
+		t.Errorf("ClusterBalancedWeights(nil) = %v, want no weights", got)
+	}
+}
+
+func TestWeightedInverseDocumentFrequency(t *testing.T) {
+
+	documents := [][]string{
+		{"x", "y"},
+		{"x"},
+		nil,
+		{"z"},
+	}
+
+	tests := []struct {
+		name      string
+		term      string
+		weights   []float64
+		weighting InvDocWeighting
+		want      float64
+	}{
+		// 4 = 0.5 + 2 + 1.5 weighted documents, the nil one is skipped.
+		{"weighted", "x", []float64{0.5, 2.0, 7.0, 1.5}, InvDocWeightingLog, math.Log(4.0 / 2.5)},
+		{"weighted rare", "y", []float64{0.5, 2.0, 7.0, 1.5}, InvDocWeightingLog, math.Log(4.0 / 0.5)},
+		{"weighted smooth", "z", []float64{0.5, 2.0, 7.0, 1.5}, InvDocWeightingLogSmooth, math.Log(1.0 + 4.0/1.5)},
+		{"weighted max", "y", []float64{0.5, 2.0, 7.0, 1.5}, InvDocWeightingLogMax, math.Log(2.5 / 1.5)},
+		{"zero weights", "x", []float64{0.0, 0.0, 0.0, 0.0}, InvDocWeightingLog, 0.0},
+		// Documents 1 to 3 lack a weight and count with 1.0.
+		{"short weights", "x", []float64{0.5}, InvDocWeightingLog, math.Log(2.5 / 1.5)},
+		{"no weights", "x", nil, InvDocWeightingLog, math.Log(3.0 / 2.0)},
+	}
+
+	for _, test := range tests {
+
+		if got := WeightedInverseDocumentFrequency(test.term, false, documents, test.weights, test.weighting); !almostEqual(got, test.want) {
+			t.Errorf("%s: WeightedInverseDocumentFrequency(%s) = %v, want %v", test.name, test.term, got, test.want)
+		}
+	}
+
+	// Without weights, all documents count the same.
+	if got, want := WeightedInverseDocumentFrequency("x", false, documents, nil, InvDocWeightingLog), InverseDocumentFrequency("x", false, documents, InvDocWeightingLog); got != want {
+		t.Errorf("unweighted idf = %v, want %v", got, want)
+	}
+}
+
+func TestDocumentAges(t *testing.T) {
+
+	now := time.Date(2020, time.March, 10, 12, 0, 0, 0, time.UTC)
+	timestamps := []time.Time{now.Add(-48 * time.Hour), now, now.Add(12 * time.Hour)}
+
+	tests := []struct {
+		name string
+		unit time.Duration
+		want []float64
+	}{
+		{"days", 24 * time.Hour, []float64{2.0, 0.0, -0.5}},
+		{"hours", time.Hour, []float64{48.0, 0.0, -12.0}},
+		{"zero unit", 0, []float64{float64(48 * time.Hour), 0.0, float64(-12 * time.Hour)}},
+		{"negative unit", -time.Hour, []float64{float64(48 * time.Hour), 0.0, float64(-12 * time.Hour)}},
+	}
+
+	for _, test := range tests {
+
+		if got := DocumentAges(timestamps, now, test.unit); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: DocumentAges = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTimeDecayWeights(t *testing.T) {
+
+	ages := []float64{2.0, 0.0, -0.5}
+
+	tests := []struct {
+		lambda float64
+		want   []float64
+	}{
+		{0.5, []float64{math.Exp(-1.0), 1.0, math.Exp(0.25)}},
+		{0.0, []float64{1.0, 1.0, 1.0}},
+	}
+
+	for _, test := range tests {
+
+		got := TimeDecayWeights(ages, test.lambda)
+		for i := range test.want {
+
+			if !almostEqual(got[i], test.want[i]) {
+				t.Errorf("lambda %v: weight %d = %v, want %v", test.lambda, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestDecayedInverseDocumentFrequency(t *testing.T) {
+
+	documents := [][]string{
+		{"x"},
+		{"x", "y"},
+		{"y"},
+		{"y"},
+	}
+
+	// Halve the weight per unit of age: 1, 1/2, 1/4 and 2 from the future.
+	ages := []float64{0.0, 1.0, 2.0, -1.0}
+	lambda := math.Ln2
+
+	tests := []struct {
+		term string
+		want float64
+	}{
+		{"x", math.Log(3.75 / 1.5)},
+		{"y", math.Log(3.75 / 2.75)},
+		{"z", 0.0},
+	}
+
+	idfs := DecayedInverseDocumentFrequencies(documents, ages, lambda, InvDocWeightingLog)
+
+	for _, test := range tests {
+
+		if got := DecayedInverseDocumentFrequency(test.term, false, documents, ages, lambda, InvDocWeightingLog); !almostEqual(got, test.want) {
+			t.Errorf("DecayedInverseDocumentFrequency(%s) = %v, want %v", test.term, got, test.want)
+		}
+
+		if !almostEqual(idfs[test.term], test.want) {
+			t.Errorf("DecayedInverseDocumentFrequencies[%s] = %v, want %v", test.term, idfs[test.term], test.want)
+		}
+	}
+
+	// Without decay, the plain idf remains.
+	if got, want := DecayedInverseDocumentFrequency("x", false, documents, ages, 0.0, InvDocWeightingLog), math.Log(2.0); !almostEqual(got, want) {
+		t.Errorf("idf without decay = %v, want %v", got, want)
+	}
+}
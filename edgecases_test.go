@@ -0,0 +1,181 @@
+package tfidf
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// Collects all values of the supplied maps.
+func mapValues(maps ...map[string]float64) []float64 {
+
+	values := make([]float64, 0)
+	for _, m := range maps {
+
+		for _, value := range m {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Flattens the supplied matrix into one slice.
+func flatten(rows ...[]float64) []float64 {
+
+	values := make([]float64, 0)
+	for _, row := range rows {
+		values = append(values, row...)
+	}
+
+	return values
+}
+
+// Flattens the supplied vectors into one slice.
+func flattenMaps(vectors []map[string]float64) []float64 {
+	return mapValues(vectors...)
+}
+
+// Every exported function consuming a corpus, a document or a term must
+// not return Inf or NaN for empty inputs, alone or in any combination.
+func TestEmptyInputsYieldFiniteValues(t *testing.T) {
+
+	corpora := map[string][][]string{
+		"nil corpus":        nil,
+		"corpus of empties": {{}, {}},
+		"corpus":            {{"a", "b", "a"}, {"b", "c"}},
+	}
+
+	documents := map[string][]string{
+		"nil document":   nil,
+		"empty document": {},
+		"document":       {"a", "zz"},
+	}
+
+	terms := []string{"", "a", "zz"}
+
+	for corpusName, corpus := range corpora {
+
+		for docName, doc := range documents {
+
+			for _, term := range terms {
+
+				for tfWeighting := TermWeightingBinary; tfWeighting <= TermWeightingDoubleK; tfWeighting++ {
+
+					for idfWeighting := InvDocWeightingUnary; idfWeighting <= InvDocWeightingProb; idfWeighting++ {
+
+						query := []string{term}
+
+						results := map[string][]float64{
+							"TermFrequency":                        {TermFrequency(term, true, doc, tfWeighting)},
+							"TermFrequencyDoubleK":                 {TermFrequencyDoubleK(term, false, doc, DoubleNormalizationK)},
+							"NormalizedTermFrequency":              {NormalizedTermFrequency(term, false, doc, 0, tfWeighting)},
+							"MaxTermFrequency":                     {MaxTermFrequency(doc)},
+							"TermFrequencies":                      TermFrequencies(doc, corpus),
+							"TermFrequenciesMap":                   mapValues(TermFrequenciesMap(doc, corpus, tfWeighting)),
+							"BatchTermFrequencies":                 flatten(BatchTermFrequencies([][]string{doc}, []string{term}, tfWeighting)...),
+							"InverseDocumentFrequency":             {InverseDocumentFrequency(term, true, corpus, idfWeighting)},
+							"InverseDocumentFrequencies":           mapValues(InverseDocumentFrequencies(corpus, idfWeighting)),
+							"NormalizedInverseDocumentFrequencies": mapValues(NormalizedInverseDocumentFrequencies(corpus, idfWeighting)),
+							"DecayedInverseDocumentFrequency":      {DecayedInverseDocumentFrequency(term, false, corpus, nil, 0.5, idfWeighting)},
+							"TfIdf":                                mapValues(TfIdf(doc, corpus, tfWeighting, idfWeighting)),
+							"TfIdfMatrix":                          flattenMaps(TfIdfMatrix(corpus, tfWeighting, idfWeighting)),
+							"SimilarityMatrix":                     flatten(SimilarityMatrix(corpus, tfWeighting, idfWeighting)...),
+							"AnomalyScores":                        AnomalyScores(corpus, tfWeighting, idfWeighting),
+							"DocumentNorms":                        DocumentNorms(corpus, tfWeighting, idfWeighting),
+							"RestrictedTfIdfVectors":               flattenMaps(RestrictedTfIdfVectors(corpus, map[string]bool{term: true}, tfWeighting, idfWeighting)),
+							"IDFInfluence":                         IDFInfluence(corpus, idfWeighting),
+							"WeightedLCS":                          {WeightedLCS(doc, query, corpus, idfWeighting)},
+							"ScoreWeightedQuery":                   {ScoreWeightedQuery(map[string]float64{term: 2.0}, doc, corpus, tfWeighting, idfWeighting)},
+							"OntologyScores":                       mapValues(OntologyScores(doc, corpus, map[string][]string{term: {term}}, tfWeighting, idfWeighting)),
+							"WeightedEmbedding":                    WeightedEmbedding(doc, corpus, map[string][]float64{"a": {1.0, 2.0}}, tfWeighting, idfWeighting),
+							"QueryCoverage":                        {QueryCoverage(query, corpus, idfWeighting).MeanIDF, QueryCoverage(query, corpus, idfWeighting).OOVRate},
+							"Vectorizer":                           NewVectorizer(tfWeighting, idfWeighting).FitTransform(append([][]string{doc}, corpus...))[0],
+							"Corpus":                               {NewCorpus(corpus, idfWeighting).IDF(term)},
+							"DocumentFrequencyCounter":             {NewDocumentFrequencyCounter().IDF(term, idfWeighting)},
+							"QueryLikelihood":                      {QueryLikelihood(query, doc, corpus, DirichletMu)},
+							"QueryLikelihoodUnsmoothed":            {QueryLikelihood(query, doc, corpus, 0.0)},
+							"QueryLikelihoodJelinekMercer":         {QueryLikelihoodJelinekMercer(query, doc, corpus, 0.0)},
+							"BM25":                                 {BM25(query, doc, corpus, BM25K1, BM25B)},
+							"RSJWeights":                           mapValues(RSJWeights(query, corpus, nil, corpus)),
+							"CosineSimilarity":                     {CosineSimilarity(TfIdf(doc, corpus, tfWeighting, idfWeighting), nil)},
+							"JaccardSimilarity":                    {JaccardSimilarity(doc, query)},
+							"DiceSimilarity":                       {DiceSimilarity(doc, query)},
+							"TermEntropy":                          {TermEntropy(term, corpus)},
+							"EffectiveVocabularySize":              {EffectiveVocabularySize(corpus)},
+							"HashingVectorizer":                    SignedHashingVectorizer(doc, 4),
+						}
+
+						for _, score := range RankDocuments(doc, corpus, tfWeighting, idfWeighting) {
+							results["RankDocuments"] = append(results["RankDocuments"], score.Score)
+						}
+
+						for _, score := range TopTerms(doc, corpus, len(doc), tfWeighting, idfWeighting) {
+							results["TopTerms"] = append(results["TopTerms"], score.Score)
+						}
+
+						exponent, rSquared := ZipfFit(corpus)
+						results["ZipfFit"] = []float64{exponent, rSquared}
+
+						for name, values := range results {
+
+							for _, value := range values {
+
+								if math.IsInf(value, 0) || math.IsNaN(value) {
+									t.Errorf("%s: %v for %s, %s, term %q, weightings %d/%d",
+										name, value, corpusName, docName, term, tfWeighting, idfWeighting)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyInputsDefinedResults(t *testing.T) {
+
+	corpus := [][]string{{"a", "b"}, {"b"}}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"idf on empty corpus", InverseDocumentFrequency("a", false, nil, InvDocWeightingLog), 0.0},
+		{"unary idf on empty corpus", InverseDocumentFrequency("a", false, nil, InvDocWeightingUnary), 0.0},
+		{"log max idf on corpus of empties", InverseDocumentFrequency("a", false, [][]string{{}}, InvDocWeightingLogMax), 0.0},
+		{"tf of empty term", TermFrequency("", false, []string{"a"}, TermWeightingRaw), 0.0},
+		{"tf in empty document", TermFrequency("a", false, nil, TermWeightingDoubleHalf), 0.0},
+		{"normalized tf in empty document", NormalizedTermFrequency("a", false, nil, 0, TermWeightingRaw), 0.0},
+		{"query likelihood on empty corpus", QueryLikelihood([]string{"a"}, []string{"a"}, nil, DirichletMu), 0.0},
+		{"unsmoothed query likelihood of empty document", QueryLikelihood([]string{"a"}, nil, corpus, 0.0), 0.0},
+		{"unsmoothed query likelihood of absent term", QueryLikelihood([]string{"a"}, []string{"b"}, corpus, 0.0), MinLogProbability},
+		{"BM25 on empty corpus", BM25([]string{"a"}, []string{"a"}, nil, BM25K1, BM25B), 0.0},
+		{"cosine of zero vectors", CosineSimilarity(nil, nil), 0.0},
+		{"jaccard of empty documents", JaccardSimilarity(nil, []string{}), 1.0},
+	}
+
+	for _, test := range tests {
+
+		if test.got != test.want {
+			t.Errorf("%s = %v, want %v", test.name, test.got, test.want)
+		}
+	}
+
+	// An empty corpus yields empty, non-nil vectors.
+	if frequencies := TermFrequencies([]string{"a"}, nil); frequencies == nil || len(frequencies) != 0 {
+		t.Errorf("TermFrequencies on empty corpus = %#v, want empty non-nil slice", frequencies)
+	}
+
+	// An empty document yields zeros over the corpus vocabulary.
+	if got, want := fmt.Sprint(TermFrequencies(nil, corpus)), "[0 0]"; got != want {
+		t.Errorf("TermFrequencies of empty document = %s, want %s", got, want)
+	}
+
+	// Documents missing a query term still rank below those containing it.
+	if withTerm, withoutTerm := QueryLikelihood([]string{"a"}, []string{"a", "b"}, corpus, 0.0), QueryLikelihood([]string{"a"}, []string{"b"}, corpus, 0.0); withTerm <= withoutTerm {
+		t.Errorf("unsmoothed query likelihood with term %v not above without %v", withTerm, withoutTerm)
+	}
+}
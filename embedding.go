@@ -0,0 +1,69 @@
+package tfidf
+
+import (
+	"sort"
+)
+
+// Functions
+
+// Computes an embedding of the tokenized doc as the tf-idf weighted
+// average of caller supplied term vectors. Each distinct term of doc
+// contributes its vector from termVectors scaled by its tf-idf weight
+// relative to documents, and the sum is divided by the total weight.
+// Terms without a vector are skipped. Terms are visited in lexicographic
+// order and the dimension of the embedding is the one of the first term
+// with a vector, terms whose vector has a different dimension are skipped
+// as well. This makes the result deterministic. If no term contributes
+// any weight, nil is returned.
+func WeightedEmbedding(doc []string, documents [][]string, termVectors map[string][]float64, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []float64 {
+
+	// Vectorize doc relative to the corpus.
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+	weights := tfIdfVector(doc, idfs, tfWeighting)
+
+	// Visit terms in a fixed order, map order is random.
+	terms := make([]string, 0, len(weights))
+	for term := range weights {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	var embedding []float64
+	totalWeight := 0.0
+
+	// Range over all weighted terms of doc.
+	for _, term := range terms {
+
+		weight := weights[term]
+
+		termVector, exists := termVectors[term]
+		if !exists {
+			continue
+		}
+
+		// Dimension is determined by the first term with a vector.
+		if embedding == nil {
+			embedding = make([]float64, len(termVector))
+		}
+
+		if len(termVector) != len(embedding) {
+			continue
+		}
+
+		for i, value := range termVector {
+			embedding[i] += weight * value
+		}
+
+		totalWeight += weight
+	}
+
+	if totalWeight == 0.0 {
+		return nil
+	}
+
+	for i := range embedding {
+		embedding[i] /= totalWeight
+	}
+
+	return embedding
+}
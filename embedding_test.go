@@ -0,0 +1,41 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWeightedEmbedding(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "c", "d"},
+		{"x"},
+	}
+
+	// "a" comes first and fixes the dimension, "b" does not fit.
+	termVectors := map[string][]float64{
+		"a": {1.0, 0.0},
+		"b": {0.0, 1.0, 5.0},
+		"c": {0.0, 2.0},
+	}
+
+	want := []float64{0.5, 1.0}
+
+	first := WeightedEmbedding(documents[0], documents, termVectors, TermWeightingRaw, InvDocWeightingLog)
+	if len(first) != len(want) || !almostEqual(first[0], want[0]) || !almostEqual(first[1], want[1]) {
+		t.Fatalf("WeightedEmbedding = %v, want %v", first, want)
+	}
+
+	// Repeated calls yield the exact same result.
+	for i := 0; i < 50; i++ {
+
+		if got := WeightedEmbedding(documents[0], documents, termVectors, TermWeightingRaw, InvDocWeightingLog); !reflect.DeepEqual(got, first) {
+			t.Fatalf("call %d: WeightedEmbedding = %v, want %v", i, got, first)
+		}
+	}
+
+	// Without any weighted term with a vector, there is no embedding.
+	if got := WeightedEmbedding([]string{"d", "x"}, documents, termVectors, TermWeightingRaw, InvDocWeightingLog); got != nil {
+		t.Errorf("WeightedEmbedding without vectors = %v, want nil", got)
+	}
+}
@@ -0,0 +1,48 @@
+package tfidf_test
+
+import (
+	"fmt"
+
+	tfidf "github.com/numbleroot/go-tfidf"
+)
+
+func ExampleTfIdf() {
+
+	documents := [][]string{
+		{"cat", "sat", "mat"},
+		{"dog", "sat", "log"},
+		{"cat", "cat", "hat"},
+	}
+
+	// Term frequency and inverse document frequency weightings are
+	// distinct types, thus swapping them fails to compile:
+	//
+	//	tfidf.TfIdf(documents[2], documents, tfidf.InvDocWeightingLog, tfidf.TermWeightingRaw)
+	//
+	// cannot use tfidf.InvDocWeightingLog (constant 1 of int type
+	// tfidf.InvDocWeighting) as tfidf.TermWeighting value in argument.
+	vector := tfidf.TfIdf(documents[2], documents, tfidf.TermWeightingRaw, tfidf.InvDocWeightingLog)
+
+	fmt.Printf("cat: %.4f\n", vector["cat"])
+	fmt.Printf("hat: %.4f\n", vector["hat"])
+	fmt.Printf("sat: %.4f\n", vector["sat"])
+
+	// Output:
+	// cat: 0.8109
+	// hat: 1.0986
+	// sat: 0.0000
+}
+
+func ExampleInvDocWeighting_Validate() {
+
+	// Weightings converted from untrusted input, e.g. a config file,
+	// are still plain integers and might be out of range.
+	weighting := tfidf.InvDocWeighting(7)
+
+	if err := weighting.Validate(); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// tfidf: unknown weighting scheme: inverse document weighting 7
+}
@@ -0,0 +1,57 @@
+package tfidf
+
+import (
+	"hash/fnv"
+)
+
+// Functions
+
+// Applies the hashing trick to the tokenized doc: each token is mapped
+// to one of nFeatures buckets by its 64 bit FNV-1a hash and the raw
+// term counts are accumulated per bucket. This yields vectors of fixed
+// length without keeping any vocabulary, e.g. for streaming or very
+// large corpora, at the price of collisions: distinct terms sharing a
+// bucket become indistinguishable and add up. The fewer buckets, the
+// more collisions, thus nFeatures should be well above the expected
+// vocabulary size. Bucketing is deterministic across runs and machines.
+// For nFeatures <= 0, an empty vector is returned.
+func HashingVectorizer(doc []string, nFeatures int) []float64 {
+	return hashingVectorizer(doc, nFeatures, false)
+}
+
+// Works like HashingVectorizer but each token adds +1 or -1 to its
+// bucket, depending on another bit of its hash. Colliding terms then
+// tend to cancel out instead of piling up, which keeps inner products
+// between hashed vectors unbiased. Bucket values may become negative.
+func SignedHashingVectorizer(doc []string, nFeatures int) []float64 {
+	return hashingVectorizer(doc, nFeatures, true)
+}
+
+// Shared implementation of the hashing vectorizers. If
+// signed is set to true, the sign is derived from the hash.
+func hashingVectorizer(doc []string, nFeatures int, signed bool) []float64 {
+
+	if nFeatures <= 0 {
+		return make([]float64, 0)
+	}
+
+	vector := make([]float64, nFeatures)
+
+	for _, token := range doc {
+
+		hash := fnv.New64a()
+		hash.Write([]byte(token))
+		sum := hash.Sum64()
+
+		// The bucket is the hash modulo nFeatures,
+		// the sign is taken from its highest bit.
+		value := 1.0
+		if signed && (sum>>63) == 1 {
+			value = -1.0
+		}
+
+		vector[sum%uint64(nFeatures)] += value
+	}
+
+	return vector
+}
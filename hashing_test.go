@@ -0,0 +1,58 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHashingVectorizer(t *testing.T) {
+
+	// Buckets are pinned, they must not change across runs or machines.
+	// "cat" and "bee" collide in bucket 7 with opposite signs.
+	doc := []string{"cat", "dog", "bird", "cat", "bee", "owl"}
+
+	tests := []struct {
+		name   string
+		vector []float64
+		want   []float64
+	}{
+		{"unsigned", HashingVectorizer(doc, 8), []float64{0, 1, 1, 0, 0, 1, 0, 3}},
+		{"signed", SignedHashingVectorizer(doc, 8), []float64{0, -1, -1, 0, 0, 1, 0, -1}},
+	}
+
+	for _, test := range tests {
+
+		if !reflect.DeepEqual(test.vector, test.want) {
+			t.Errorf("%s: vector = %v, want %v", test.name, test.vector, test.want)
+		}
+	}
+
+	for _, nFeatures := range []int{1, 3, 1024} {
+
+		vector := HashingVectorizer(doc, nFeatures)
+		if len(vector) != nFeatures {
+			t.Errorf("HashingVectorizer(%d) has length %d", nFeatures, len(vector))
+		}
+
+		// All tokens end up in some bucket.
+		total := 0.0
+		for _, value := range vector {
+			total += value
+		}
+
+		if total != float64(len(doc)) {
+			t.Errorf("HashingVectorizer(%d) counts %v tokens, want %d", nFeatures, total, len(doc))
+		}
+
+		if signed := SignedHashingVectorizer(doc, nFeatures); len(signed) != nFeatures {
+			t.Errorf("SignedHashingVectorizer(%d) has length %d", nFeatures, len(signed))
+		}
+	}
+
+	for _, nFeatures := range []int{0, -1} {
+
+		if got := HashingVectorizer(doc, nFeatures); got == nil || len(got) != 0 {
+			t.Errorf("HashingVectorizer(%d) = %#v, want empty vector", nFeatures, got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package tfidf
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Functions
+
+// Computes the tf-idf vector of every document in the corpus, exactly
+// like calling TfIdf for each of them, but computes the inverse document
+// frequencies only once and spreads the per-document work over a bounded
+// pool of goroutines, one per CPU. The result is in the same order as
+// the supplied documents. Neither documents nor any package state is
+// modified, so this is safe to call concurrently.
+func TfIdfMatrix(documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []map[string]float64 {
+
+	// The background context is never done.
+	matrix, _ := TfIdfMatrixContext(context.Background(), documents, tfWeighting, idfWeighting)
+
+	return matrix
+}
+
+// Works like TfIdfMatrix but stops handing out documents to the workers
+// once ctx is done, e.g. because the client went away. In that case the
+// workers finish their current document and ctx.Err() is returned along
+// with a nil matrix.
+func TfIdfMatrixContext(ctx context.Context, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) ([]map[string]float64, error) {
+
+	// Compute idf only once, it is only read from here on.
+	idfs, err := InverseDocumentFrequenciesContext(ctx, documents, idfWeighting)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := make([]map[string]float64, len(documents))
+
+	// Bound number of workers by number of CPUs.
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(documents) {
+		numWorkers = len(documents)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < numWorkers; w++ {
+
+		wg.Add(1)
+		go func() {
+
+			defer wg.Done()
+
+			// Each worker writes to distinct indices only.
+			for i := range jobs {
+				matrix[i] = fullVector(documents[i], idfs, tfWeighting, MultiplyTfIdf)
+			}
+		}()
+	}
+
+	// Hand out documents until all are done or ctx is.
+	cancelled := false
+	for i := 0; !cancelled && i < len(documents); i++ {
+
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			cancelled = true
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	if cancelled {
+		return nil, ctx.Err()
+	}
+
+	return matrix, nil
+}
+
+// Computes the pairwise cosine similarities of all documents in the
+// corpus. Each tf-idf vector is computed and L2 normalized only once,
+// based on inverse document frequencies computed once for the corpus,
+// after which the similarity of two documents is the dot product of
+// their vectors. As the matrix is symmetric, only the upper triangle is
+// computed and mirrored. The diagonal is always 1.0, even for documents
+// without any weighted term, whose similarity to all others is 0.0.
+func SimilarityMatrix(documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) [][]float64 {
+
+	// Compute and normalize tf-idf vectors of all documents.
+	vectors := tfIdfVectors(documents, tfWeighting, idfWeighting)
+	for i := range vectors {
+		NormalizeInPlace(vectors[i])
+	}
+
+	matrix := make([][]float64, len(vectors))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(vectors))
+		matrix[i][i] = 1.0
+	}
+
+	// Fill upper triangle and mirror it.
+	for i := range vectors {
+
+		for j := i + 1; j < len(vectors); j++ {
+			similarity := dotProduct(vectors[i], vectors[j])
+			matrix[i][j] = similarity
+			matrix[j][i] = similarity
+		}
+	}
+
+	return matrix
+}
@@ -0,0 +1,165 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Generates a reproducible corpus of numDocs tokenized documents of
+// docLength terms each, drawn with a skew towards common terms from a
+// vocabulary of vocabularySize terms.
+func syntheticCorpus(numDocs int, docLength int, vocabularySize int) [][]string {
+
+	random := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(random, 1.1, 1.0, uint64(vocabularySize-1))
+
+	documents := make([][]string, numDocs)
+	for i := range documents {
+
+		documents[i] = make([]string, docLength)
+		for j := range documents[i] {
+			documents[i][j] = fmt.Sprintf("term%d", zipf.Uint64())
+		}
+	}
+
+	return documents
+}
+
+func TestTfIdfMatrixMatchesTfIdf(t *testing.T) {
+
+	documents := syntheticCorpus(20, 15, 60)
+	documents[7] = nil
+	documents[8] = []string{}
+
+	for tfWeighting := TermWeightingBinary; tfWeighting <= TermWeightingDoubleK; tfWeighting++ {
+
+		for idfWeighting := InvDocWeightingUnary; idfWeighting <= InvDocWeightingProb; idfWeighting++ {
+
+			matrix := TfIdfMatrix(documents, tfWeighting, idfWeighting)
+
+			if len(matrix) != len(documents) {
+				t.Fatalf("TfIdfMatrix returned %d vectors for %d documents", len(matrix), len(documents))
+			}
+
+			for i, document := range documents {
+
+				if want := TfIdf(document, documents, tfWeighting, idfWeighting); !reflect.DeepEqual(matrix[i], want) {
+					t.Errorf("weightings %d/%d: vector %d = %v, want %v", tfWeighting, idfWeighting, i, matrix[i], want)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkTfIdfMatrix(b *testing.B) {
+
+	documents := syntheticCorpus(1000, 100, 5000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		TfIdfMatrix(documents, TermWeightingLog, InvDocWeightingLog)
+	}
+}
+
+func BenchmarkTfIdfMatrixSequential(b *testing.B) {
+
+	documents := syntheticCorpus(1000, 100, 5000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+
+		idfs := InverseDocumentFrequencies(documents, InvDocWeightingLog)
+		for _, document := range documents {
+			fullVector(document, idfs, TermWeightingLog, MultiplyTfIdf)
+		}
+	}
+}
+
+func TestTfIdfMatrixContext(t *testing.T) {
+
+	documents := syntheticCorpus(20, 15, 60)
+
+	matrix, err := TfIdfMatrixContext(context.Background(), documents, TermWeightingLog, InvDocWeightingLog)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if want := TfIdfMatrix(documents, TermWeightingLog, InvDocWeightingLog); !reflect.DeepEqual(matrix, want) {
+		t.Errorf("TfIdfMatrixContext differs from TfIdfMatrix")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"cancelled", cancelled, context.Canceled},
+		{"deadline exceeded", expired, context.DeadlineExceeded},
+	}
+
+	for _, test := range tests {
+
+		if matrix, err := TfIdfMatrixContext(test.ctx, documents, TermWeightingLog, InvDocWeightingLog); matrix != nil || !errors.Is(err, test.want) {
+			t.Errorf("%s: matrix of %d vectors, error %v, want nil, %v", test.name, len(matrix), err, test.want)
+		}
+	}
+}
+
+func TestSimilarityMatrix(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b"},
+		{"b", "c", "c"},
+		{"d"},
+		{},
+	}
+
+	matrix := SimilarityMatrix(documents, TermWeightingRaw, InvDocWeightingLogSmooth)
+
+	// Only "b" is shared. Out of four documents, including the empty
+	// one, idf(a) = idf(c) = log(1 + 4 / 1) and idf(b) = log(1 + 4 / 2).
+	idfShared, idfOwn := math.Log(3.0), math.Log(5.0)
+	want01 := (idfShared * idfShared) / (math.Sqrt(idfOwn*idfOwn+idfShared*idfShared) * math.Sqrt(idfShared*idfShared+4.0*idfOwn*idfOwn))
+
+	if !almostEqual(matrix[0][1], want01) {
+		t.Errorf("similarity of documents 0 and 1 = %v, want %v", matrix[0][1], want01)
+	}
+
+	for i := range documents {
+
+		if matrix[i][i] != 1.0 {
+			t.Errorf("diagonal entry %d = %v, want 1", i, matrix[i][i])
+		}
+
+		for j := range documents {
+
+			if matrix[i][j] != matrix[j][i] {
+				t.Errorf("entries %d,%d and %d,%d differ: %v vs %v", i, j, j, i, matrix[i][j], matrix[j][i])
+			}
+
+			if want := CosineSimilarity(TfIdf(documents[i], documents, TermWeightingRaw, InvDocWeightingLogSmooth), TfIdf(documents[j], documents, TermWeightingRaw, InvDocWeightingLogSmooth)); i != j && !almostEqual(matrix[i][j], want) {
+				t.Errorf("entry %d,%d = %v, want %v", i, j, matrix[i][j], want)
+			}
+		}
+	}
+
+	// The empty document is similar to nothing but itself.
+	for j := 0; j < 3; j++ {
+
+		if matrix[3][j] != 0.0 {
+			t.Errorf("similarity of empty document to %d = %v, want 0", j, matrix[3][j])
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tfidf
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Structs and types
+
+// A persistable inverse document frequency model. It carries the
+// weighting scheme the idf values were computed with, so a reloaded
+// model is not accidentally combined with a different scheme.
+type Model struct {
+	Weighting InvDocWeighting    `json:"weighting"`
+	IDF       map[string]float64 `json:"idf"`
+}
+
+// Functions
+
+// Computes the inverse document frequencies of the supplied corpus
+// with the supplied weighting scheme and wraps them into a Model.
+func NewModel(documents [][]string, weighting InvDocWeighting) *Model {
+
+	return &Model{
+		Weighting: weighting,
+		IDF:       InverseDocumentFrequencies(documents, weighting),
+	}
+}
+
+// Writes the model as JSON to w.
+func (m *Model) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m)
+}
+
+// Replaces the contents of the model by the JSON encoded
+// model read from r, as written by Save. Models carrying an
+// unknown weighting scheme are rejected with an error wrapping
+// ErrUnknownWeighting and leave m unchanged.
+func (m *Model) Load(r io.Reader) error {
+
+	var loaded Model
+
+	if err := json.NewDecoder(r).Decode(&loaded); err != nil {
+		return err
+	}
+
+	if err := loaded.Weighting.Validate(); err != nil {
+		return err
+	}
+
+	// A model without terms still has a usable map.
+	if loaded.IDF == nil {
+		loaded.IDF = make(map[string]float64)
+	}
+
+	*m = loaded
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package tfidf
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModelRoundTrip(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "sat", "mat"},
+		{"dog", "sat", "log"},
+		{"cat", "cat", "hat"},
+	}
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		model := NewModel(documents, weighting)
+
+		var buffer bytes.Buffer
+		if err := model.Save(&buffer); err != nil {
+			t.Fatalf("weighting %d: Save failed: %v", weighting, err)
+		}
+
+		var loaded Model
+		if err := loaded.Load(&buffer); err != nil {
+			t.Fatalf("weighting %d: Load failed: %v", weighting, err)
+		}
+
+		if !reflect.DeepEqual(&loaded, model) {
+			t.Errorf("weighting %d: loaded model %v, want %v", weighting, loaded, model)
+		}
+	}
+}
+
+func TestModelLoadRejectsInvalidInput(t *testing.T) {
+
+	original := &Model{Weighting: InvDocWeightingLog, IDF: map[string]float64{"cat": 1.0}}
+
+	tests := []struct {
+		name    string
+		input   string
+		unknown bool
+	}{
+		{"unknown weighting", `{"weighting": 9, "idf": {"dog": 2.0}}`, true},
+		{"malformed JSON", `{"weighting": 1, "idf": `, false},
+	}
+
+	for _, test := range tests {
+
+		model := &Model{Weighting: original.Weighting, IDF: map[string]float64{"cat": 1.0}}
+
+		err := model.Load(strings.NewReader(test.input))
+		if err == nil {
+			t.Fatalf("%s: Load succeeded, want error", test.name)
+		}
+
+		if errors.Is(err, ErrUnknownWeighting) != test.unknown {
+			t.Errorf("%s: Load error %v, wrapping ErrUnknownWeighting want %t", test.name, err, test.unknown)
+		}
+
+		if !reflect.DeepEqual(model, original) {
+			t.Errorf("%s: model changed to %v, want %v", test.name, model, original)
+		}
+	}
+
+	// Models without terms load with a usable map.
+	var empty Model
+	if err := empty.Load(strings.NewReader(`{"weighting": 0}`)); err != nil || empty.IDF == nil {
+		t.Errorf("Load of model without terms = %v with IDF %v, want empty map", err, empty.IDF)
+	}
+}
@@ -0,0 +1,101 @@
+package tfidf
+
+// Structs and types
+
+// Maintains the number of documents and the per-term document counts of
+// an online corpus where documents arrive and leave continuously, e.g. a
+// sliding window. Unlike Corpus, it keeps no inverted index but counts
+// only, thus removing a document requires the document itself. Idf values
+// are computed from the current counts on every query. A
+// DocumentFrequencyCounter is not safe for concurrent use.
+type DocumentFrequencyCounter struct {
+	numDocs int
+	counts  map[string]int
+}
+
+// Functions
+
+// Creates a new, empty DocumentFrequencyCounter.
+func NewDocumentFrequencyCounter() *DocumentFrequencyCounter {
+
+	return &DocumentFrequencyCounter{
+		counts: make(map[string]int),
+	}
+}
+
+// Adds a tokenized document to the counts. Nil
+// documents are ignored, see ValidateDocuments.
+func (d *DocumentFrequencyCounter) Add(doc []string) {
+
+	if doc == nil {
+		return
+	}
+
+	d.numDocs++
+
+	for term := range distinctTerms(doc) {
+		d.counts[term]++
+	}
+}
+
+// Removes a tokenized document previously added via Add from the
+// counts. Counts never drop below zero: terms reaching zero are pruned
+// and terms not counted at all are ignored, as is removing a document
+// from an empty counter or removing a nil document.
+func (d *DocumentFrequencyCounter) Remove(doc []string) {
+
+	if d.numDocs == 0 || doc == nil {
+		return
+	}
+
+	d.numDocs--
+
+	for term := range distinctTerms(doc) {
+
+		if d.counts[term] <= 1 {
+			delete(d.counts, term)
+		} else {
+			d.counts[term]--
+		}
+	}
+}
+
+// Returns the number of documents currently counted.
+func (d *DocumentFrequencyCounter) NumDocuments() int {
+	return d.numDocs
+}
+
+// Returns the number of documents currently counted containing term.
+func (d *DocumentFrequencyCounter) DocumentFrequency(term string) int {
+	return d.counts[term]
+}
+
+// Returns the inverse document frequency of an already tokenized term
+// weighted by the supplied scheme, reflecting the current counts.
+func (d *DocumentFrequencyCounter) IDF(term string, weighting InvDocWeighting) float64 {
+
+	// Log maximum weighting relates to the most common term.
+	maxDocsWithTerm := 0
+	if weighting == InvDocWeightingLogMax {
+
+		for _, count := range d.counts {
+
+			if count > maxDocsWithTerm {
+				maxDocsWithTerm = count
+			}
+		}
+	}
+
+	return weightInverseDocumentFrequency(float64(d.numDocs), float64(d.counts[term]), float64(maxDocsWithTerm), weighting)
+}
+
+// Returns the set of distinct terms of a tokenized document.
+func distinctTerms(doc []string) map[string]bool {
+
+	terms := make(map[string]bool, len(doc))
+	for _, token := range doc {
+		terms[token] = true
+	}
+
+	return terms
+}
@@ -0,0 +1,82 @@
+package tfidf
+
+import (
+	"testing"
+)
+
+func TestDocumentFrequencyCounter(t *testing.T) {
+
+	window := [][]string{
+		{"cat", "cat", "dog"},
+		{"dog", "bird"},
+		{"fish"},
+	}
+
+	counter := NewDocumentFrequencyCounter()
+	for _, document := range window {
+		counter.Add(document)
+	}
+	counter.Add(nil)
+
+	if got := counter.NumDocuments(); got != 3 {
+		t.Errorf("NumDocuments = %d, want 3", got)
+	}
+
+	if got := counter.DocumentFrequency("cat"); got != 1 {
+		t.Errorf("DocumentFrequency(cat) = %d, want 1", got)
+	}
+
+	if got := counter.DocumentFrequency("dog"); got != 2 {
+		t.Errorf("DocumentFrequency(dog) = %d, want 2", got)
+	}
+
+	// Slide the window: drop the oldest document, add a new one.
+	counter.Remove(window[0])
+	counter.Add([]string{"cat"})
+	window = append(window[1:], []string{"cat"})
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		for _, term := range []string{"cat", "dog", "bird", "fish", "cow"} {
+
+			if got, want := counter.IDF(term, weighting), InverseDocumentFrequency(term, false, window, weighting); !almostEqual(got, want) {
+				t.Errorf("weighting %d: IDF(%s) = %v, want %v", weighting, term, got, want)
+			}
+		}
+	}
+}
+
+func TestDocumentFrequencyCounterNeverNegative(t *testing.T) {
+
+	counter := NewDocumentFrequencyCounter()
+
+	// Removing from an empty counter is ignored.
+	counter.Remove([]string{"cat"})
+
+	if got := counter.NumDocuments(); got != 0 {
+		t.Errorf("NumDocuments after removal from empty counter = %d, want 0", got)
+	}
+
+	counter.Add([]string{"cat"})
+	counter.Remove([]string{"cat", "dog"})
+	counter.Remove(nil)
+
+	if got := counter.NumDocuments(); got != 0 {
+		t.Errorf("NumDocuments = %d, want 0", got)
+	}
+
+	for _, term := range []string{"cat", "dog"} {
+
+		if got := counter.DocumentFrequency(term); got != 0 {
+			t.Errorf("DocumentFrequency(%s) = %d, want 0", term, got)
+		}
+	}
+
+	if len(counter.counts) != 0 {
+		t.Errorf("counts not pruned: %v", counter.counts)
+	}
+
+	if got := counter.IDF("cat", InvDocWeightingLog); got != 0.0 {
+		t.Errorf("IDF on empty counter = %v, want 0", got)
+	}
+}
@@ -0,0 +1,89 @@
+package tfidf
+
+import (
+	"sort"
+)
+
+// Structs and types
+
+// A document of a corpus, identified by its index, and its score
+// against a query. Matched and Unmatched are only filled in by
+// RankDocumentsExplained and are nil otherwise.
+type DocScore struct {
+	Index int
+	Score float64
+
+	// Distinct query terms contributing to the score, sorted.
+	Matched []string
+	// Distinct query terms not contributing, sorted. These are
+	// absent from the document or weigh 0.0 in the corpus.
+	Unmatched []string
+}
+
+// Functions
+
+// Ranks all documents of the corpus against the tokenized query by the
+// cosine similarity of their tf-idf vectors. Inverse document frequencies
+// are computed once over the corpus and shared by the query and all
+// documents. The result holds one DocScore per document, sorted by
+// descending score with ties broken by ascending document index.
+func RankDocuments(query []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []DocScore {
+	return rankDocuments(query, documents, tfWeighting, idfWeighting, false)
+}
+
+// Works like RankDocuments but additionally reports per result which
+// query terms drove the match and which matched nothing, e.g. to tell
+// users that a term is unknown to the corpus. A query term contributes
+// if it carries a non-zero weight in both the query and the document
+// vector.
+func RankDocumentsExplained(query []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []DocScore {
+	return rankDocuments(query, documents, tfWeighting, idfWeighting, true)
+}
+
+// Shared implementation of the ranking functions. If explain
+// is set to true, matched and unmatched terms are filled in.
+func rankDocuments(query []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting, explain bool) []DocScore {
+
+	// Compute idf only once for the whole corpus.
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+	queryVector := tfIdfVector(query, idfs, tfWeighting)
+
+	// Distinct query terms in a fixed order.
+	var queryTerms []string
+	if explain {
+		queryTerms = Vocabulary([][]string{query})
+	}
+
+	scores := make([]DocScore, len(documents))
+	for i, document := range documents {
+
+		docVector := tfIdfVector(document, idfs, tfWeighting)
+
+		scores[i] = DocScore{
+			Index: i,
+			Score: CosineSimilarity(queryVector, docVector),
+		}
+
+		if explain {
+
+			scores[i].Matched = make([]string, 0)
+			scores[i].Unmatched = make([]string, 0)
+
+			for _, term := range queryTerms {
+
+				if queryVector[term] != 0.0 && docVector[term] != 0.0 {
+					scores[i].Matched = append(scores[i].Matched, term)
+				} else {
+					scores[i].Unmatched = append(scores[i].Unmatched, term)
+				}
+			}
+		}
+	}
+
+	// Order by score, keeping corpus order among equals.
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores
+}
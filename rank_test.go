@@ -0,0 +1,73 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankDocuments(t *testing.T) {
+
+	documents := TokenizeDocuments([]string{
+		"Dogs bark at night",
+		"The quick brown fox jumps over the lazy dog",
+		"Foxes are quick and foxes are clever",
+		"Cats sleep all day",
+	})
+
+	query := TokenizeDocument("quick fox")
+	ranking := RankDocuments(query, documents, TermWeightingRaw, InvDocWeightingLog)
+
+	if len(ranking) != len(documents) {
+		t.Fatalf("RankDocuments returned %d scores for %d documents", len(ranking), len(documents))
+	}
+
+	// The document repeating both query terms matches best.
+	if got, want := []int{ranking[0].Index, ranking[1].Index}, []int{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("top documents = %v, want %v", got, want)
+	}
+
+	if ranking[0].Score <= ranking[1].Score {
+		t.Errorf("best score %v not above second %v", ranking[0].Score, ranking[1].Score)
+	}
+
+	// Non-matching documents tie at zero and keep corpus order.
+	if got, want := []int{ranking[2].Index, ranking[3].Index}, []int{0, 3}; !reflect.DeepEqual(got, want) || ranking[2].Score != 0.0 || ranking[3].Score != 0.0 {
+		t.Errorf("remaining documents = %v, want %v with score 0", ranking[2:], want)
+	}
+
+	for _, score := range ranking {
+
+		if want := CosineSimilarity(TfIdf(query, documents, TermWeightingRaw, InvDocWeightingLog), TfIdf(documents[score.Index], documents, TermWeightingRaw, InvDocWeightingLog)); !almostEqual(score.Score, want) {
+			t.Errorf("score of document %d = %v, want %v", score.Index, score.Score, want)
+		}
+
+		if score.Matched != nil || score.Unmatched != nil {
+			t.Errorf("document %d explained without being asked to", score.Index)
+		}
+	}
+}
+
+func TestRankDocumentsExplained(t *testing.T) {
+
+	documents := [][]string{{"x", "y"}, {"a", "b", "a"}, {"b", "c"}}
+
+	ranking := RankDocumentsExplained([]string{"a", "b", "unknown"}, documents, TermWeightingRaw, InvDocWeightingLog)
+
+	top := ranking[0]
+	if top.Index != 1 {
+		t.Fatalf("top document = %d, want 1", top.Index)
+	}
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(top.Matched, want) {
+		t.Errorf("Matched = %v, want %v", top.Matched, want)
+	}
+
+	if want := []string{"unknown"}; !reflect.DeepEqual(top.Unmatched, want) {
+		t.Errorf("Unmatched = %v, want %v", top.Unmatched, want)
+	}
+
+	// Plain ranking does not explain anything.
+	if plain := RankDocuments([]string{"a"}, documents, TermWeightingRaw, InvDocWeightingLog); plain[0].Matched != nil || plain[0].Unmatched != nil {
+		t.Errorf("RankDocuments filled in terms: %+v", plain[0])
+	}
+}
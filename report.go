@@ -0,0 +1,40 @@
+package tfidf
+
+import (
+	"fmt"
+	"io"
+)
+
+// Functions
+
+// Writes a human-readable report of the top k tf-idf terms of each
+// document in the corpus to w. Every document is listed by its index,
+// followed by one line per term holding the term and its score.
+// Like TopTerms, a k <= 0 lists no terms at all, only the documents,
+// and a k exceeding the number of terms of a document lists all of
+// them. The first write error encountered is returned.
+func WriteTopTermsReport(w io.Writer, documents [][]string, k int, tfWeighting TermWeighting, idfWeighting InvDocWeighting) error {
+
+	if k < 0 {
+		k = 0
+	}
+
+	// Vectorize the whole corpus once.
+	vectors := tfIdfVectors(documents, tfWeighting, idfWeighting)
+
+	for i, vector := range vectors {
+
+		if _, err := fmt.Fprintf(w, "Document %d:\n", i); err != nil {
+			return err
+		}
+
+		for _, top := range topTerms(vector, k) {
+
+			if _, err := fmt.Fprintf(w, "\t%s\t%.6f\n", top.Term, top.Score); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package tfidf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Fails every write after the first ok ones.
+type failingWriter struct {
+	ok int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+
+	if w.ok <= 0 {
+		return 0, errWrite
+	}
+
+	w.ok--
+
+	return len(p), nil
+}
+
+func TestWriteTopTermsReport(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "b"},
+		{"c"},
+	}
+
+	tests := []struct {
+		name string
+		k    int
+		want string
+	}{
+		{"top term", 1, "Document 0:\n\tb\t1.386294\nDocument 1:\n\tc\t0.693147\n"},
+		{"all terms", 5, "Document 0:\n\tb\t1.386294\n\ta\t0.693147\nDocument 1:\n\tc\t0.693147\n"},
+		{"no terms", 0, "Document 0:\nDocument 1:\n"},
+		{"negative k", -1, "Document 0:\nDocument 1:\n"},
+	}
+
+	for _, test := range tests {
+
+		var buffer bytes.Buffer
+
+		if err := WriteTopTermsReport(&buffer, documents, test.k, TermWeightingRaw, InvDocWeightingLog); err != nil {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+
+		if got := buffer.String(); got != test.want {
+			t.Errorf("%s: report = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWriteTopTermsReportWriteError(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "b"},
+		{"c"},
+	}
+
+	// Fail on a document header as well as on a term line.
+	for _, ok := range []int{0, 1, 3} {
+
+		if err := WriteTopTermsReport(&failingWriter{ok: ok}, documents, 5, TermWeightingRaw, InvDocWeightingLog); !errors.Is(err, errWrite) {
+			t.Errorf("failing after %d writes: error %v, want %v", ok, err, errWrite)
+		}
+	}
+}
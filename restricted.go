@@ -0,0 +1,103 @@
+package tfidf
+
+// Functions
+
+// Computes the inverse document frequencies of only those terms present
+// in the allowed set, e.g. a curated feature list. The corpus is scanned
+// exactly once and all tokens outside the allowed set are skipped right
+// away. Allowed terms not occurring in the corpus are not part of the result.
+func RestrictedInverseDocumentFrequencies(documents [][]string, allowed map[string]bool, weighting InvDocWeighting) map[string]float64 {
+
+	// Count documents containing each allowed term.
+	counts := make(map[string]float64)
+
+	// Range over all documents.
+	for _, document := range documents {
+
+		// Terms already counted for the current document.
+		seen := make(map[string]bool)
+
+		// Range over all tokens in current document.
+		for _, token := range document {
+
+			// Short-circuit everything outside the allowed set.
+			if !allowed[token] || seen[token] {
+				continue
+			}
+
+			counts[token] += 1.0
+			seen[token] = true
+		}
+	}
+
+	// Log maximum weighting relates to the most common allowed term.
+	maxDocsWithTerm := maxCount(counts)
+
+	idfs := make(map[string]float64, len(counts))
+
+	for term, count := range counts {
+		idfs[term] = weightInverseDocumentFrequency(float64(corpusSize(documents)), count, maxDocsWithTerm, weighting)
+	}
+
+	return idfs
+}
+
+// Computes the sparse tf-idf vectors of all documents of the corpus
+// restricted to the allowed set of terms. Neither idf computation nor
+// vectorization look at tokens outside the allowed set.
+func RestrictedTfIdfVectors(documents [][]string, allowed map[string]bool, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []map[string]float64 {
+
+	idfs := RestrictedInverseDocumentFrequencies(documents, allowed, idfWeighting)
+
+	vectors := make([]map[string]float64, len(documents))
+
+	for i, document := range documents {
+
+		// Only count allowed tokens.
+		counts := make(map[string]float64)
+		for _, token := range document {
+
+			if allowed[token] {
+				counts[token] += 1.0
+			}
+		}
+
+		// Double normalization is relative to the most
+		// frequent allowed term of the document.
+		maxFrequency := maxCount(counts)
+
+		vector := make(map[string]float64, len(counts))
+		for term, count := range counts {
+			vector[term] = weightTermFrequency(count, maxFrequency, tfWeighting) * idfs[term]
+		}
+
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+// Prunes the vocabulary of the corpus by document frequency and returns
+// the set of terms kept, ready to be used as the allowed set of the
+// restricted functions. A term is kept if it occurs in at least minDF
+// documents and in at most a fraction of maxDFRatio of all documents,
+// which drops both rare terms and terms common to nearly every document.
+// A maxDFRatio of 1.0 disables the upper bound.
+func FilterVocabulary(documents [][]string, minDF int, maxDFRatio float64) map[string]bool {
+
+	allowed := make(map[string]bool)
+
+	numDocs := float64(corpusSize(documents))
+	if numDocs == 0.0 {
+		return allowed
+	}
+
+	for term, count := range weightedDocumentFrequencies(documents, nil) {
+
+		if count >= float64(minDF) && (count/numDocs) <= maxDFRatio {
+			allowed[term] = true
+		}
+	}
+
+	return allowed
+}
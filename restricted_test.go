@@ -0,0 +1,64 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterVocabulary(t *testing.T) {
+
+	documents := [][]string{
+		{"the", "cat", "dog"},
+		{"the", "cat", "cat"},
+		{"the", "rare"},
+		{"the"},
+	}
+
+	tests := []struct {
+		minDF      int
+		maxDFRatio float64
+		want       map[string]bool
+	}{
+		{1, 1.0, map[string]bool{"the": true, "cat": true, "dog": true, "rare": true}},
+		{2, 1.0, map[string]bool{"the": true, "cat": true}},
+		{1, 0.5, map[string]bool{"cat": true, "dog": true, "rare": true}},
+		{2, 0.75, map[string]bool{"cat": true}},
+		{1, 0.2, map[string]bool{}},
+		{5, 1.0, map[string]bool{}},
+	}
+
+	for _, test := range tests {
+
+		if got := FilterVocabulary(documents, test.minDF, test.maxDFRatio); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("FilterVocabulary(%d, %v) = %v, want %v", test.minDF, test.maxDFRatio, got, test.want)
+		}
+	}
+
+	if got := FilterVocabulary(nil, 0, 1.0); len(got) != 0 {
+		t.Errorf("FilterVocabulary of empty corpus = %v, want empty set", got)
+	}
+}
+
+func TestRestrictedInverseDocumentFrequencies(t *testing.T) {
+
+	documents := [][]string{
+		{"the", "cat", "dog"},
+		{"the", "cat", "cat"},
+		{"the", "rare"},
+	}
+
+	allowed := FilterVocabulary(documents, 2, 1.0)
+	restricted := RestrictedInverseDocumentFrequencies(documents, allowed, InvDocWeightingLog)
+	full := InverseDocumentFrequencies(documents, InvDocWeightingLog)
+
+	if len(restricted) != len(allowed) {
+		t.Fatalf("RestrictedInverseDocumentFrequencies = %v, want terms %v", restricted, allowed)
+	}
+
+	for term := range allowed {
+
+		if !almostEqual(restricted[term], full[term]) {
+			t.Errorf("restricted idf[%s] = %v, want %v", term, restricted[term], full[term])
+		}
+	}
+}
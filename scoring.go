@@ -0,0 +1,269 @@
+package tfidf
+
+import (
+	"math"
+)
+
+// Constants
+
+const (
+
+	// Typical value of the Dirichlet prior for QueryLikelihood.
+	DirichletMu float64 = 2000.0
+	// Default term frequency saturation parameter of BM25.
+	BM25K1 float64 = 1.5
+	// Default document length normalization parameter of BM25.
+	BM25B float64 = 0.75
+)
+
+var (
+	// Log probability of query terms the language models assign a
+	// probability of zero, the log of the smallest positive float64.
+	MinLogProbability = math.Log(math.SmallestNonzeroFloat64)
+)
+
+// Functions
+
+// Scores how strongly the tokenized doc engages each concept of the
+// supplied ontology. The ontology maps a concept term to its synonyms.
+// Concept terms and synonyms are lowercased and stemmed like
+// TokenizeDocument does and the score of a concept is the sum of the
+// tf-idf weights (relative to documents) of all distinct resulting terms
+// in doc. Synonyms collapsing into the same stem are only counted once.
+func OntologyScores(doc []string, documents [][]string, ontology map[string][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) map[string]float64 {
+
+	// Initialize result map.
+	scores := make(map[string]float64)
+
+	// Vectorize doc relative to the corpus.
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+	vector := tfIdfVector(doc, idfs, tfWeighting)
+
+	// Range over all concepts.
+	for concept, synonyms := range ontology {
+
+		// Collect the distinct stemmed terms of this concept.
+		terms := make(map[string]bool)
+		terms[StemTerm(concept)] = true
+
+		for _, synonym := range synonyms {
+			terms[StemTerm(synonym)] = true
+		}
+
+		// Sum up tf-idf weights of all concept terms.
+		score := 0.0
+		for term := range terms {
+			score += vector[term]
+		}
+
+		scores[concept] = score
+	}
+
+	return scores
+}
+
+// Scores the tokenized doc against a tokenized query with the query
+// likelihood language model using Dirichlet smoothing. The result is the
+// log probability log P(query | doc), where each query term q contributes
+// log((tf(q, doc) + mu * P(q | C)) / (len(doc) + mu)) and P(q | C) is the
+// collection model estimated from documents. Query terms that do not occur
+// anywhere in the corpus are skipped, as their probability would be zero.
+// Commonly used values for mu are in the range of DirichletMu. An empty
+// query or corpus results in a log probability of 0.0, as does an empty
+// doc in combination with a mu of 0.0. Without smoothing (mu of 0.0),
+// query terms absent from doc have a probability of zero. Instead of
+// -Inf, they contribute MinLogProbability, which keeps scores finite
+// while still ranking such documents below all others.
+func QueryLikelihood(query []string, doc []string, documents [][]string, mu float64) float64 {
+
+	collection, total := collectionFrequencies(documents)
+	frequencies := termCounts(doc)
+
+	// Nothing to smooth an empty document with.
+	if (float64(len(doc)) + mu) <= 0.0 {
+		return 0.0
+	}
+
+	// Sum up log probabilities of all query terms.
+	likelihood := 0.0
+
+	for _, term := range query {
+
+		// Skip terms unknown to the collection model.
+		if collection[term] == 0.0 {
+			continue
+		}
+
+		probability := (frequencies[term] + mu*(collection[term]/total)) / (float64(len(doc)) + mu)
+		likelihood += logProbability(probability)
+	}
+
+	return likelihood
+}
+
+// Works like QueryLikelihood but uses Jelinek-Mercer smoothing, that is
+// each query term q contributes log((1 - lambda) * P(q | doc) + lambda * P(q | C)).
+// lambda is expected to be in (0, 1]. An empty query or corpus results
+// in a log probability of 0.0. Zero probabilities, i.e. a lambda of 0.0
+// and a query term absent from doc, contribute MinLogProbability.
+func QueryLikelihoodJelinekMercer(query []string, doc []string, documents [][]string, lambda float64) float64 {
+
+	collection, total := collectionFrequencies(documents)
+	frequencies := termCounts(doc)
+
+	// Sum up log probabilities of all query terms.
+	likelihood := 0.0
+
+	for _, term := range query {
+
+		// Skip terms unknown to the collection model.
+		if collection[term] == 0.0 {
+			continue
+		}
+
+		// Maximum likelihood estimate of term in document.
+		docProbability := 0.0
+		if len(doc) > 0 {
+			docProbability = frequencies[term] / float64(len(doc))
+		}
+
+		probability := (1.0-lambda)*docProbability + lambda*(collection[term]/total)
+		likelihood += logProbability(probability)
+	}
+
+	return likelihood
+}
+
+// Returns the log of a language model probability, replacing
+// the -Inf of a zero probability by MinLogProbability.
+func logProbability(probability float64) float64 {
+
+	if probability <= 0.0 {
+		return MinLogProbability
+	}
+
+	return math.Log(probability)
+}
+
+// Computes Robertson-Spärck-Jones relevance weights for all distinct terms
+// of the tokenized query from relevance judgments. With R relevant documents
+// of which r contain the term and S non-relevant documents of which s contain
+// it, the weight of a term is
+//
+//	log(((r + 0.5) * (S - s + 0.5)) / ((R - r + 0.5) * (s + 0.5)))
+//
+// using the standard 0.5 smoothing of every cell of the 2x2 contingency table.
+// If nonRelevant is nil, every document of allDocuments that is not relevant is
+// treated as non-relevant, i.e. S = N - R and s = n - r with N documents in
+// allDocuments of which n contain the term. allDocuments is expected to
+// include the relevant documents in that case. Otherwise the count S - s of
+// non-relevant documents without the term might turn negative, which would
+// make the weight NaN, thus it is clamped to 0.0 before smoothing.
+func RSJWeights(query []string, relevant [][]string, nonRelevant [][]string, allDocuments [][]string) map[string]float64 {
+
+	weights := make(map[string]float64)
+
+	numRelevant := float64(corpusSize(relevant))
+
+	for _, term := range query {
+
+		// Only weigh each term once.
+		if _, exists := weights[term]; exists {
+			continue
+		}
+
+		relevantWithTerm := float64(DocumentFrequency(term, false, relevant))
+
+		var numNonRelevant, nonRelevantWithTerm float64
+
+		if nonRelevant != nil {
+			numNonRelevant = float64(corpusSize(nonRelevant))
+			nonRelevantWithTerm = float64(DocumentFrequency(term, false, nonRelevant))
+		} else {
+			// Derive non-relevant counts from the whole collection.
+			numNonRelevant = math.Max(0.0, float64(corpusSize(allDocuments))-numRelevant)
+			nonRelevantWithTerm = math.Max(0.0, float64(DocumentFrequency(term, false, allDocuments))-relevantWithTerm)
+		}
+
+		// Non-relevant documents without the term.
+		nonRelevantWithoutTerm := math.Max(0.0, numNonRelevant-nonRelevantWithTerm)
+
+		weights[term] = math.Log(((relevantWithTerm + 0.5) * (nonRelevantWithoutTerm + 0.5)) /
+			((numRelevant - relevantWithTerm + 0.5) * (nonRelevantWithTerm + 0.5)))
+	}
+
+	return weights
+}
+
+// Scores the tokenized doc against the tokenized queryTerms with Okapi
+// BM25 relative to the corpus documents. Each query term q contributes
+//
+//	idf(q) * (tf(q, doc) * (k1 + 1)) / (tf(q, doc) + k1 * (1 - b + b * len(doc) / avgdl))
+//
+// where avgdl is the average document length of the corpus and idf is
+// log(1 + (N - n + 0.5) / (n + 0.5)) with N documents of which n contain
+// q, which is never negative. k1 controls the saturation of the term
+// frequency and b the strength of the length normalization, commonly used
+// values are BM25K1 and BM25B. Repeated query terms contribute repeatedly.
+// An empty corpus results in a score of 0.0.
+func BM25(queryTerms []string, doc []string, documents [][]string, k1 float64, b float64) float64 {
+
+	numDocs := float64(corpusSize(documents))
+	if numDocs == 0.0 {
+		return 0.0
+	}
+
+	// Average document length of the corpus.
+	avgLength := 0.0
+	for _, document := range documents {
+		avgLength += float64(len(document)) / numDocs
+	}
+
+	// Length normalization of doc, skipped for a corpus of empty documents.
+	normalization := 1.0
+	if avgLength > 0.0 {
+		normalization = 1.0 - b + b*(float64(len(doc))/avgLength)
+	}
+
+	frequencies := termCounts(doc)
+
+	// Sum up the contributions of all query terms.
+	score := 0.0
+
+	for _, term := range queryTerms {
+
+		frequency := frequencies[term]
+		if frequency == 0.0 {
+			continue
+		}
+
+		numDocsWithTerm := float64(DocumentFrequency(term, false, documents))
+		idf := math.Log(1.0 + ((numDocs - numDocsWithTerm + 0.5) / (numDocsWithTerm + 0.5)))
+
+		score += idf * (frequency * (k1 + 1.0)) / (frequency + k1*normalization)
+	}
+
+	return score
+}
+
+// Scores the tokenized doc against a weighted query mapping already
+// tokenized terms to their weights, e.g. to boost some of the terms.
+// The query vector holds the weight of each term multiplied by its idf
+// relative to documents and the score is its cosine similarity to the
+// tf-idf vector of doc. Query terms absent from the corpus contribute
+// zero, as do terms with a weight of 0.0.
+func ScoreWeightedQuery(query map[string]float64, doc []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) float64 {
+
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+
+	// Weigh each query term by its boost and idf.
+	queryVector := make(map[string]float64, len(query))
+	for term, weight := range query {
+
+		if idf, exists := idfs[term]; exists {
+			queryVector[term] = weight * idf
+		}
+	}
+
+	return CosineSimilarity(queryVector, tfIdfVector(doc, idfs, tfWeighting))
+}
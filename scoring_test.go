@@ -0,0 +1,218 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBM25(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "a", "b"},
+		{"b", "c"},
+		{"c"},
+	}
+
+	// Three documents of average length 2, doc has length 3:
+	// idf(a) = log(1 + 2.5 / 1.5), idf(b) = log(1 + 1.5 / 2.5).
+	idfA := math.Log(1.0 + 2.5/1.5)
+	idfB := math.Log(1.0 + 1.5/2.5)
+	normalization := 1.0 - 0.75 + 0.75*(3.0/2.0)
+
+	tests := []struct {
+		name  string
+		query []string
+		k1    float64
+		b     float64
+		want  float64
+	}{
+		{"single term", []string{"a"}, 1.5, 0.75, idfA * (2.0 * 2.5) / (2.0 + 1.5*normalization)},
+		{"two terms", []string{"a", "b"}, 1.5, 0.75, idfA*(2.0*2.5)/(2.0+1.5*normalization) + idfB*(1.0*2.5)/(1.0+1.5*normalization)},
+		{"repeated term", []string{"b", "b"}, 1.5, 0.75, 2.0 * idfB * (1.0 * 2.5) / (1.0 + 1.5*normalization)},
+		{"no length normalization", []string{"a"}, 1.2, 0.0, idfA * (2.0 * 2.2) / (2.0 + 1.2)},
+		{"absent term", []string{"c", "z"}, 1.5, 0.75, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := BM25(test.query, documents[0], documents, test.k1, test.b); !almostEqual(got, test.want) {
+			t.Errorf("%s: BM25 = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	// A term in every document still contributes positively.
+	if got := BM25([]string{"c"}, []string{"c"}, [][]string{{"c"}, {"c"}}, BM25K1, BM25B); got <= 0.0 {
+		t.Errorf("BM25 of term in all documents = %v, want > 0", got)
+	}
+}
+
+func TestScoreWeightedQuery(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "sofa"},
+		{"dog", "yard"},
+		{"bird"},
+	}
+
+	plain := map[string]float64{"cat": 1.0, "dog": 1.0}
+	boosted := map[string]float64{"cat": 3.0, "dog": 1.0}
+
+	// Boosting a term raises documents containing it
+	// and lowers those only containing the others.
+	catPlain := ScoreWeightedQuery(plain, documents[0], documents, TermWeightingRaw, InvDocWeightingLog)
+	catBoosted := ScoreWeightedQuery(boosted, documents[0], documents, TermWeightingRaw, InvDocWeightingLog)
+
+	if catBoosted <= catPlain {
+		t.Errorf("boosted score %v not above plain score %v", catBoosted, catPlain)
+	}
+
+	dogPlain := ScoreWeightedQuery(plain, documents[1], documents, TermWeightingRaw, InvDocWeightingLog)
+	dogBoosted := ScoreWeightedQuery(boosted, documents[1], documents, TermWeightingRaw, InvDocWeightingLog)
+
+	if dogBoosted >= dogPlain {
+		t.Errorf("score %v of document without boosted term not below plain score %v", dogBoosted, dogPlain)
+	}
+
+	// Equal weights score like an unweighted query.
+	ranking := RankDocuments([]string{"cat", "dog"}, documents, TermWeightingRaw, InvDocWeightingLog)
+	if ranking[0].Index != 0 || !almostEqual(ranking[0].Score, catPlain) {
+		t.Errorf("ranked score %v, want %v", ranking[0], catPlain)
+	}
+
+	// A weight of zero drops the term.
+	if got := ScoreWeightedQuery(map[string]float64{"cat": 0.0, "dog": 1.0}, documents[0], documents, TermWeightingRaw, InvDocWeightingLog); got != 0.0 {
+		t.Errorf("score with zero weighted term = %v, want 0", got)
+	}
+}
+
+func TestRSJWeights(t *testing.T) {
+
+	// Textbook RSJ weight with N documents, n of them containing the
+	// term, R relevant ones, r of them containing the term.
+	rsj := func(N float64, n float64, R float64, r float64) float64 {
+		return math.Log(((r + 0.5) * (N - n - R + r + 0.5)) / ((n - r + 0.5) * (R - r + 0.5)))
+	}
+
+	relevant := [][]string{
+		{"a", "b"},
+		{"a"},
+	}
+
+	nonRelevant := [][]string{
+		{"b", "c"},
+		{"c"},
+		{"a", "c"},
+	}
+
+	allDocuments := append(append([][]string{}, relevant...), nonRelevant...)
+	query := []string{"a", "b", "c", "z", "a"}
+
+	want := map[string]float64{
+		"a": rsj(5, 3, 2, 2),
+		"b": rsj(5, 2, 2, 1),
+		"c": rsj(5, 3, 2, 0),
+		"z": rsj(5, 0, 2, 0),
+	}
+
+	for name, weights := range map[string]map[string]float64{
+		"derived non-relevant":  RSJWeights(query, relevant, nil, allDocuments),
+		"explicit non-relevant": RSJWeights(query, relevant, nonRelevant, nil),
+	} {
+
+		if len(weights) != len(want) {
+			t.Errorf("%s: got %d weights, want %d", name, len(weights), len(want))
+		}
+
+		for term, w := range want {
+
+			if !almostEqual(weights[term], w) {
+				t.Errorf("%s: weight(%s) = %v, want %v", name, term, weights[term], w)
+			}
+		}
+	}
+
+	// Relevant documents missing from allDocuments leave more documents
+	// with the term than non-relevant ones: N - n - R + r = 3 - 3 - 3 + 0.
+	weights := RSJWeights([]string{"a"}, [][]string{{"x"}, {"y"}, {"z"}}, nil, [][]string{{"a"}, {"a"}, {"a"}})
+	if got, want := weights["a"], math.Log((0.5*0.5)/(3.5*3.5)); math.IsNaN(got) || !almostEqual(got, want) {
+		t.Errorf("clamped weight(a) = %v, want %v", got, want)
+	}
+}
+
+func TestOntologyScores(t *testing.T) {
+
+	// Idfs: car = log(2), road = bike = tree = log(4).
+	documents := [][]string{
+		{"car", "road"},
+		{"car"},
+		{"bike"},
+		{"tree"},
+	}
+
+	doc := []string{"car", "car", "road"}
+
+	ontology := map[string][]string{
+		"vehicle": {"car", "Cars", "automobile"},
+		"street":  {"road", "roads"},
+		"nature":  {"tree"},
+	}
+
+	// "car" and "Cars" collapse into one stem, which is counted once.
+	want := map[string]float64{
+		"vehicle": 2.0 * math.Log(2.0),
+		"street":  math.Log(4.0),
+		"nature":  0.0,
+	}
+
+	scores := OntologyScores(doc, documents, ontology, TermWeightingRaw, InvDocWeightingLog)
+	if len(scores) != len(want) {
+		t.Fatalf("OntologyScores = %v, want %v", scores, want)
+	}
+
+	for concept, score := range want {
+
+		if !almostEqual(scores[concept], score) {
+			t.Errorf("score of %s = %v, want %v", concept, scores[concept], score)
+		}
+	}
+}
+
+func TestQueryLikelihood(t *testing.T) {
+
+	// Collection model: P(a) = 2 / 5, P(b) = 1 / 5, P(c) = 2 / 5.
+	documents := [][]string{
+		{"a", "b"},
+		{"a", "c", "c"},
+	}
+
+	doc := documents[1]
+
+	// "x" is unknown to the collection and skipped.
+	query := []string{"a", "b", "x"}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"dirichlet", QueryLikelihood(query, doc, documents, 2.0), math.Log((1.0+2.0*0.4)/5.0) + math.Log((0.0+2.0*0.2)/5.0)},
+		{"dirichlet unsmoothed", QueryLikelihood(query, doc, documents, 0.0), math.Log(1.0/3.0) + MinLogProbability},
+		{"dirichlet empty doc", QueryLikelihood(query, nil, documents, 2.0), math.Log(0.4) + math.Log(0.2)},
+		{"jelinek-mercer", QueryLikelihoodJelinekMercer(query, doc, documents, 0.5), math.Log(0.5/3.0+0.5*0.4) + math.Log(0.5*0.2)},
+		{"jelinek-mercer collection only", QueryLikelihoodJelinekMercer(query, doc, documents, 1.0), math.Log(0.4) + math.Log(0.2)},
+		{"jelinek-mercer unsmoothed", QueryLikelihoodJelinekMercer(query, doc, documents, 0.0), math.Log(1.0/3.0) + MinLogProbability},
+		{"empty corpus", QueryLikelihood(query, doc, nil, 2.0), 0.0},
+	}
+
+	for _, test := range tests {
+
+		if !almostEqual(test.got, test.want) {
+			t.Errorf("%s: log likelihood = %v, want %v", test.name, test.got, test.want)
+		}
+	}
+
+	// The smoothed model prefers the document containing more query terms.
+	if first, second := QueryLikelihood(query, documents[0], documents, 2.0), QueryLikelihood(query, documents[1], documents, 2.0); first <= second {
+		t.Errorf("log likelihood of matching document %v not above %v", first, second)
+	}
+}
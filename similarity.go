@@ -0,0 +1,142 @@
+package tfidf
+
+import (
+	"math"
+)
+
+// Functions
+
+// Computes the cosine of the angle between two tf-idf vectors given
+// as term to weight maps, e.g. as returned by TfIdf. The maps may hold
+// different key sets, terms missing from one vector contribute zero.
+// If either one is a zero vector, 0.0 is returned instead of NaN.
+func CosineSimilarity(vecA map[string]float64, vecB map[string]float64) float64 {
+
+	normA := vectorNorm(vecA)
+	normB := vectorNorm(vecB)
+
+	if normA == 0.0 || normB == 0.0 {
+		return 0.0
+	}
+
+	return dotProduct(vecA, vecB) / (normA * normB)
+}
+
+// Computes an order-sensitive similarity of two tokenized documents: the
+// weight of their longest common subsequence, where each matched term counts
+// with its inverse document frequency relative to documents. The result is
+// normalized to [0, 1] by 2 * LCS / (weight(a) + weight(b)), weight(x) being
+// the summed idf of all tokens of x. Negative idf values are clamped to zero.
+// If neither document carries any weight, 0.0 is returned.
+func WeightedLCS(a []string, b []string, documents [][]string, idfWeighting InvDocWeighting) float64 {
+
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+
+	// Looks up the non-negative weight of a term. All terms outside
+	// the corpus vocabulary share one idf, computed on first demand.
+	absentIDF, absentKnown := 0.0, false
+	weight := func(term string) float64 {
+
+		idf, known := idfs[term]
+		if !known {
+
+			if !absentKnown {
+				absentIDF = InverseDocumentFrequency(term, false, documents, idfWeighting)
+				absentKnown = true
+			}
+			idf = absentIDF
+		}
+
+		return math.Max(0.0, idf)
+	}
+
+	// Total weights of both documents.
+	totalA, totalB := 0.0, 0.0
+	for _, token := range a {
+		totalA += weight(token)
+	}
+	for _, token := range b {
+		totalB += weight(token)
+	}
+
+	if totalA+totalB == 0.0 {
+		return 0.0
+	}
+
+	// Dynamic programming over prefixes of a and b,
+	// keeping only the previous and current row.
+	previous := make([]float64, len(b)+1)
+	current := make([]float64, len(b)+1)
+
+	for i := 1; i <= len(a); i++ {
+
+		for j := 1; j <= len(b); j++ {
+
+			if a[i-1] == b[j-1] {
+				current[j] = previous[j-1] + weight(a[i-1])
+			} else {
+				current[j] = math.Max(previous[j], current[j-1])
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return (2.0 * previous[len(b)]) / (totalA + totalB)
+}
+
+// Computes the Jaccard similarity of two tokenized documents treated
+// as sets of terms, i.e. the size of their intersection divided by the
+// size of their union. Duplicate tokens are counted once. Being purely
+// set based, it is a cheap pre-filter before computing tf-idf vectors.
+// Two empty documents are considered identical and yield 1.0.
+func JaccardSimilarity(docA []string, docB []string) float64 {
+
+	setA, setB, intersection := tokenSetOverlap(docA, docB)
+
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 1.0
+	}
+
+	return float64(intersection) / float64(union)
+}
+
+// Works like JaccardSimilarity but computes the Dice coefficient
+// 2 * |A ∩ B| / (|A| + |B|), which weighs shared terms more strongly.
+// Two empty documents yield 1.0 as well.
+func DiceSimilarity(docA []string, docB []string) float64 {
+
+	setA, setB, intersection := tokenSetOverlap(docA, docB)
+
+	if len(setA)+len(setB) == 0 {
+		return 1.0
+	}
+
+	return (2.0 * float64(intersection)) / float64(len(setA)+len(setB))
+}
+
+// Deduplicates the tokens of both documents into sets and
+// counts the number of terms present in both of them.
+func tokenSetOverlap(docA []string, docB []string) (map[string]bool, map[string]bool, int) {
+
+	setA := make(map[string]bool, len(docA))
+	for _, token := range docA {
+		setA[token] = true
+	}
+
+	setB := make(map[string]bool, len(docB))
+	for _, token := range docB {
+		setB[token] = true
+	}
+
+	intersection := 0
+	for term := range setA {
+
+		if setB[term] {
+			intersection++
+		}
+	}
+
+	return setA, setB, intersection
+}
@@ -0,0 +1,131 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+
+	tests := []struct {
+		name string
+		vecA map[string]float64
+		vecB map[string]float64
+		want float64
+	}{
+		{"identical", map[string]float64{"a": 1.0, "b": 2.0}, map[string]float64{"a": 1.0, "b": 2.0}, 1.0},
+		{"scaled", map[string]float64{"a": 1.0, "b": 2.0}, map[string]float64{"a": 3.0, "b": 6.0}, 1.0},
+		{"orthogonal", map[string]float64{"a": 1.0}, map[string]float64{"b": 1.0}, 0.0},
+		{"orthogonal with zero weights", map[string]float64{"a": 1.0, "b": 0.0}, map[string]float64{"a": 0.0, "b": 1.0}, 0.0},
+		{"partial overlap", map[string]float64{"a": 1.0, "b": 1.0}, map[string]float64{"b": 1.0, "c": 1.0}, 0.5},
+		{"partial overlap weighted", map[string]float64{"a": 3.0, "b": 4.0}, map[string]float64{"b": 1.0}, 0.8},
+		{"zero vector", map[string]float64{"a": 0.0}, map[string]float64{"a": 1.0}, 0.0},
+		{"empty vectors", map[string]float64{}, nil, 0.0},
+	}
+
+	for _, test := range tests {
+
+		got := CosineSimilarity(test.vecA, test.vecB)
+		if !almostEqual(got, test.want) {
+			t.Errorf("%s: CosineSimilarity = %v, want %v", test.name, got, test.want)
+		}
+
+		if reverse := CosineSimilarity(test.vecB, test.vecA); !almostEqual(reverse, got) {
+			t.Errorf("%s: CosineSimilarity not symmetric, %v vs %v", test.name, got, reverse)
+		}
+	}
+}
+
+func TestCosineSimilarityOfTfIdfVectors(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "sat", "mat"},
+		{"cat", "sat", "mat"},
+		{"dog", "barked", "loudly"},
+	}
+
+	vectors := make([]map[string]float64, len(documents))
+	for i, document := range documents {
+		vectors[i] = TfIdf(document, documents, TermWeightingRaw, InvDocWeightingLog)
+	}
+
+	if got := CosineSimilarity(vectors[0], vectors[1]); !almostEqual(got, 1.0) {
+		t.Errorf("similarity of identical documents = %v, want 1", got)
+	}
+
+	if got := CosineSimilarity(vectors[0], vectors[2]); got != 0.0 || math.IsNaN(got) {
+		t.Errorf("similarity of disjoint documents = %v, want 0", got)
+	}
+}
+
+func TestSetSimilarities(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		docA    []string
+		docB    []string
+		jaccard float64
+		dice    float64
+	}{
+		{"identical", []string{"a", "b", "c"}, []string{"c", "b", "a"}, 1.0, 1.0},
+		{"identical with duplicates", []string{"a", "a", "b"}, []string{"a", "b", "b"}, 1.0, 1.0},
+		{"disjoint", []string{"a", "b"}, []string{"c", "d"}, 0.0, 0.0},
+		{"overlap", []string{"a", "b", "c"}, []string{"b", "c", "d"}, 2.0 / 4.0, 4.0 / 6.0},
+		{"subset", []string{"a"}, []string{"a", "b", "c", "d"}, 1.0 / 4.0, 2.0 / 5.0},
+		{"one empty", []string{"a"}, nil, 0.0, 0.0},
+		{"both empty", nil, []string{}, 1.0, 1.0},
+	}
+
+	for _, test := range tests {
+
+		if got := JaccardSimilarity(test.docA, test.docB); !almostEqual(got, test.jaccard) {
+			t.Errorf("%s: JaccardSimilarity = %v, want %v", test.name, got, test.jaccard)
+		}
+
+		if got := DiceSimilarity(test.docA, test.docB); !almostEqual(got, test.dice) {
+			t.Errorf("%s: DiceSimilarity = %v, want %v", test.name, got, test.dice)
+		}
+	}
+}
+
+func TestWeightedLCS(t *testing.T) {
+
+	// Idfs: a = log(4 / 3), b = c = d = log(4), unknown terms 0.
+	documents := [][]string{
+		{"a", "b"},
+		{"a", "c"},
+		{"a"},
+		{"d"},
+	}
+
+	common, rare := math.Log(4.0/3.0), math.Log(4.0)
+
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want float64
+	}{
+		// "b c" is common to both, with "a" and "x" in the gaps.
+		{"gapped subsequence", []string{"b", "a", "c", "d"}, []string{"b", "x", "c"}, 2.0 * 2.0 * rare / ((3.0*rare + common) + 2.0*rare)},
+		// Either "a" or "b" matches, the heavier one counts.
+		{"heaviest subsequence", []string{"a", "b"}, []string{"b", "a"}, 2.0 * rare / (2.0 * (common + rare))},
+		{"identical", []string{"a", "b", "c"}, []string{"a", "b", "c"}, 1.0},
+		{"no shared terms", []string{"a", "b"}, []string{"c", "d"}, 0.0},
+		{"no weight", []string{"x"}, []string{"x", "y"}, 0.0},
+		{"empty", nil, []string{"a"}, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := WeightedLCS(test.a, test.b, documents, InvDocWeightingLog); !almostEqual(got, test.want) {
+			t.Errorf("%s: WeightedLCS(%q, %q) = %v, want %v", test.name, test.a, test.b, got, test.want)
+		}
+	}
+
+	// Under log max weighting, unknown terms weigh log(3 / 1) and "d"
+	// log(3 / 2), while "a" is clamped from log(3 / 4) to 0. Only "d" matches.
+	if got, want := WeightedLCS([]string{"a", "x", "d"}, []string{"y", "d"}, documents, InvDocWeightingLogMax), 2.0*math.Log(1.5)/(2.0*(math.Log(3.0)+math.Log(1.5))); !almostEqual(got, want) {
+		t.Errorf("WeightedLCS with unknown terms = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package tfidf
+
+import (
+	"strings"
+)
+
+// Functions
+
+// Generates a short context snippet of a tokenized document showing
+// where the supplied query terms appear. Query terms are lowercased and
+// stemmed like TokenizeDocument does, so they match tokens produced by
+// it. Out of all runs of window consecutive tokens, the one containing
+// the most query term occurrences is returned, joined by spaces. Ties
+// go to the earliest run. Documents shorter than window are returned
+// as a whole. If no query term occurs in tokens or window <= 0, an
+// empty string is returned.
+func Snippet(queryTerms []string, tokens []string, window int) string {
+	return HighlightedSnippet(queryTerms, tokens, window, "", "")
+}
+
+// Works like Snippet but surrounds every matched token of the
+// snippet by before and after, e.g. "<b>" and "</b>".
+func HighlightedSnippet(queryTerms []string, tokens []string, window int, before string, after string) string {
+
+	if window <= 0 {
+		return ""
+	}
+
+	// Stem query terms like the document tokens were.
+	query := make(map[string]bool, len(queryTerms))
+	for _, term := range queryTerms {
+		query[StemTerm(term)] = true
+	}
+
+	// Mark all matching positions.
+	matches := make([]bool, len(tokens))
+	for i, token := range tokens {
+		matches[i] = query[token]
+	}
+
+	if window > len(tokens) {
+		window = len(tokens)
+	}
+
+	// Slide the window over the document and keep
+	// the run with the most matches.
+	best, bestCount := -1, 0
+	count := 0
+
+	for i := range tokens {
+
+		if matches[i] {
+			count++
+		}
+
+		// Drop the token leaving the window.
+		if i >= window && matches[i-window] {
+			count--
+		}
+
+		if i >= window-1 && count > bestCount {
+			best = i - window + 1
+			bestCount = count
+		}
+	}
+
+	if best < 0 {
+		return ""
+	}
+
+	// Assemble the snippet, marking matched tokens.
+	parts := make([]string, window)
+	for i := range parts {
+
+		token := tokens[best+i]
+		if matches[best+i] {
+			token = before + token + after
+		}
+		parts[i] = token
+	}
+
+	return strings.Join(parts, " ")
+}
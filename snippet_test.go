@@ -0,0 +1,47 @@
+package tfidf
+
+import (
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+
+	tokens := []string{"a", "b", "run", "c", "d", "run", "run", "e"}
+
+	tests := []struct {
+		name   string
+		query  []string
+		window int
+		want   string
+	}{
+		{"densest window", []string{"Running"}, 3, "d run run"},
+		{"earliest of equal windows", []string{"run", "e"}, 2, "run run"},
+		{"single token window", []string{"run"}, 1, "run"},
+		{"window at start", []string{"a"}, 3, "a b run"},
+		{"window at end", []string{"e"}, 2, "run e"},
+		{"window exceeds document", []string{"b"}, 100, "a b run c d run run e"},
+		{"no match", []string{"walk"}, 3, ""},
+		{"empty window", []string{"run"}, 0, ""},
+		{"negative window", []string{"run"}, -2, ""},
+	}
+
+	for _, test := range tests {
+
+		if got := Snippet(test.query, tokens, test.window); got != test.want {
+			t.Errorf("%s: Snippet(%q, %d) = %q, want %q", test.name, test.query, test.window, got, test.want)
+		}
+	}
+
+	if got := Snippet([]string{"run"}, nil, 3); got != "" {
+		t.Errorf("Snippet of empty document = %q, want empty string", got)
+	}
+}
+
+func TestHighlightedSnippet(t *testing.T) {
+
+	tokens := TokenizeDocument("The runners were running through the park")
+
+	if got, want := HighlightedSnippet([]string{"Running", "park"}, tokens, 2, "<b>", "</b>"), "<b>run</b> <b>park</b>"; got != want {
+		t.Errorf("HighlightedSnippet = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package tfidf
+
+import (
+	"github.com/blevesearch/go-porterstemmer"
+)
+
+// Constants
+
+const (
+
+	// Minimum number of distinct surface forms that have to
+	// share one stem before soft stemming collapses them.
+	SoftStemMinForms int = 2
+)
+
+// Functions
+
+// Takes in a corpus of tokenized but unstemmed documents and
+// applies stemming only where the corpus backs it up: a term is
+// replaced by its stem if at least minForms distinct surface forms
+// present in the corpus reduce to that same stem. All other terms
+// keep their original form. Values of minForms below
+// SoftStemMinForms are raised to it. The supplied documents are
+// not modified, a new corpus is returned in which nil documents
+// stay nil.
+func SoftStem(documents [][]string, minForms int) [][]string {
+
+	if minForms < SoftStemMinForms {
+		minForms = SoftStemMinForms
+	}
+
+	// Collect the distinct surface forms per stem and
+	// remember the stem of each surface form we saw.
+	forms := make(map[string]map[string]bool)
+	stems := make(map[string]string)
+
+	// Range over all documents.
+	for _, document := range documents {
+
+		// Range over all tokens in current document.
+		for _, token := range document {
+
+			// Only stem each surface form once.
+			if _, exists := stems[token]; exists {
+				continue
+			}
+
+			stem := porterstemmer.StemString(token)
+			stems[token] = stem
+
+			if forms[stem] == nil {
+				forms[stem] = make(map[string]bool)
+			}
+			forms[stem][token] = true
+		}
+	}
+
+	// Build the soft stemmed corpus.
+	resultDocuments := make([][]string, len(documents))
+
+	for i, document := range documents {
+
+		// Nil documents are not part of the corpus.
+		if document == nil {
+			continue
+		}
+
+		resultDocument := make([]string, len(document))
+
+		for j, token := range document {
+
+			// Collapse token into its stem only if enough
+			// surface forms in the corpus share that stem.
+			stem := stems[token]
+			if len(forms[stem]) >= minForms {
+				resultDocument[j] = stem
+			} else {
+				resultDocument[j] = token
+			}
+		}
+
+		resultDocuments[i] = resultDocument
+	}
+
+	return resultDocuments
+}
+
+// Tokenizes all supplied documents without stemming them and
+// afterwards runs a corpus-aware SoftStem pass over the result.
+// Use this instead of TokenizeDocument if isolated words should
+// keep their surface form.
+func TokenizeDocumentsSoftStem(documents []string, minForms int) [][]string {
+
+	// Reserve space for the unstemmed corpus.
+	tokenized := make([][]string, len(documents))
+
+	for i, document := range documents {
+		tokenized[i] = TokenizeDocumentWithOptions(document, false)
+	}
+
+	return SoftStem(tokenized, minForms)
+}
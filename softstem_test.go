@@ -0,0 +1,66 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSoftStem(t *testing.T) {
+
+	// Three surface forms stem to "run", two to "walk", one to "jump".
+	documents := [][]string{
+		{"running", "runs", "walked"},
+		{"run", "walking"},
+		{"jumping"},
+	}
+
+	tests := []struct {
+		name     string
+		minForms int
+		want     [][]string
+	}{
+		{"default", SoftStemMinForms, [][]string{{"run", "run", "walk"}, {"run", "walk"}, {"jumping"}}},
+		{"three forms", 3, [][]string{{"run", "run", "walked"}, {"run", "walking"}, {"jumping"}}},
+		{"more forms than present", 4, documents},
+		{"one form raised", 1, [][]string{{"run", "run", "walk"}, {"run", "walk"}, {"jumping"}}},
+		{"negative raised", -3, [][]string{{"run", "run", "walk"}, {"run", "walk"}, {"jumping"}}},
+	}
+
+	for _, test := range tests {
+
+		if got := SoftStem(documents, test.minForms); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: SoftStem(%d) = %q, want %q", test.name, test.minForms, got, test.want)
+		}
+	}
+
+	// The representative of merged forms is their stem, no matter
+	// which form comes first in the corpus.
+	reversed := [][]string{{"walking", "run"}, {"walked", "runs", "running"}}
+	if got, want := SoftStem(reversed, 2), [][]string{{"walk", "run"}, {"walk", "run", "run"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SoftStem of reversed corpus = %q, want %q", got, want)
+	}
+
+	if documents[0][0] != "running" {
+		t.Errorf("SoftStem modified its input: %q", documents)
+	}
+}
+
+func TestSoftStemEmpty(t *testing.T) {
+
+	if got := SoftStem(nil, 2); len(got) != 0 {
+		t.Errorf("SoftStem of empty corpus = %q, want empty corpus", got)
+	}
+
+	if got, want := SoftStem([][]string{nil, {}, {"runs"}}, 2), [][]string{nil, {}, {"runs"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SoftStem = %q, want %q", got, want)
+	}
+}
+
+func TestTokenizeDocumentsSoftStem(t *testing.T) {
+
+	documents := []string{"The runners were running", "Runs, jumps and walks"}
+
+	if got, want := TokenizeDocumentsSoftStem(documents, 2), [][]string{{"runners", "run"}, {"run", "jumps", "walks"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeDocumentsSoftStem = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package tfidf
+
+import (
+	"math"
+)
+
+// Structs and types
+
+// A sparse vector mapping vocabulary position to its non-zero value.
+// All positions not present in the map are zero.
+type SparseVector map[int]float64
+
+// Functions
+
+// Sparse counterpart of TermFrequenciesWithVocabulary: returns the raw
+// term frequencies of compareDoc holding only the non-zero entries, keyed
+// by the term's position in the returned, lexicographically sorted corpus
+// vocabulary. Terms of compareDoc outside the vocabulary are dropped.
+func SparseTermFrequencies(compareDoc []string, documents [][]string) (SparseVector, []string) {
+
+	vocabulary := Vocabulary(documents)
+
+	// Map each term to its position.
+	positions := make(map[string]int, len(vocabulary))
+	for i, term := range vocabulary {
+		positions[term] = i
+	}
+
+	vector := make(SparseVector)
+	for term, count := range termCounts(compareDoc) {
+
+		if i, known := positions[term]; known {
+			vector[i] = count
+		}
+	}
+
+	return vector, vocabulary
+}
+
+// Converts a dense vector into its sparse form, dropping all zeros.
+func DenseToSparse(dense []float64) SparseVector {
+
+	sparse := make(SparseVector)
+
+	for i, value := range dense {
+
+		if value != 0.0 {
+			sparse[i] = value
+		}
+	}
+
+	return sparse
+}
+
+// Converts the sparse vector into a dense one of the supplied length.
+// Entries at positions beyond length are dropped.
+func (v SparseVector) Dense(length int) []float64 {
+
+	dense := make([]float64, length)
+
+	for i, value := range v {
+
+		if i >= 0 && i < length {
+			dense[i] = value
+		}
+	}
+
+	return dense
+}
+
+// Returns the euclidean (L2) norm of the sparse vector.
+func (v SparseVector) Norm() float64 {
+
+	var sum float64
+
+	for _, value := range v {
+		sum += value * value
+	}
+
+	return math.Sqrt(sum)
+}
+
+// Computes the cosine similarity of two sparse vectors directly,
+// only touching their non-zero entries. If either one is a zero
+// vector, 0.0 is returned.
+func SparseCosineSimilarity(a SparseVector, b SparseVector) float64 {
+
+	normA := a.Norm()
+	normB := b.Norm()
+
+	if normA == 0.0 || normB == 0.0 {
+		return 0.0
+	}
+
+	// Always range over the smaller vector.
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for i, value := range a {
+		dot += value * b[i]
+	}
+
+	return dot / (normA * normB)
+}
+
+// Computes the cosine similarity of two aligned dense vectors. If
+// their lengths differ, missing positions are treated as zeros. If
+// either one is a zero vector, 0.0 is returned.
+func DenseCosineSimilarity(a []float64, b []float64) float64 {
+
+	var dot, sumA, sumB float64
+
+	for i, value := range a {
+
+		sumA += value * value
+		if i < len(b) {
+			dot += value * b[i]
+		}
+	}
+
+	for _, value := range b {
+		sumB += value * value
+	}
+
+	if sumA == 0.0 || sumB == 0.0 {
+		return 0.0
+	}
+
+	return dot / (math.Sqrt(sumA) * math.Sqrt(sumB))
+}
@@ -0,0 +1,63 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSparseTermFrequencies(t *testing.T) {
+
+	documents := [][]string{
+		{"dog", "cat"},
+		{"bird", "cat", "cat"},
+	}
+
+	sparse, vocabulary := SparseTermFrequencies([]string{"cat", "cat", "dog", "fish"}, documents)
+	dense, denseVocabulary := TermFrequenciesWithVocabulary([]string{"cat", "cat", "dog", "fish"}, documents)
+
+	if !reflect.DeepEqual(vocabulary, denseVocabulary) {
+		t.Fatalf("vocabulary = %q, want %q", vocabulary, denseVocabulary)
+	}
+
+	if want := (SparseVector{1: 2.0, 2: 1.0}); !reflect.DeepEqual(sparse, want) {
+		t.Errorf("SparseTermFrequencies = %v, want %v", sparse, want)
+	}
+
+	if got := sparse.Dense(len(vocabulary)); !reflect.DeepEqual(got, dense) {
+		t.Errorf("Dense = %v, want %v", got, dense)
+	}
+
+	if got := DenseToSparse(dense); !reflect.DeepEqual(got, sparse) {
+		t.Errorf("DenseToSparse = %v, want %v", got, sparse)
+	}
+}
+
+func TestSparseAndDenseCosineSimilarityAgree(t *testing.T) {
+
+	documents := syntheticCorpus(20, 30, 50)
+	documents = append(documents, []string{})
+
+	for i := range documents {
+
+		for j := range documents {
+
+			sparseA, vocabulary := SparseTermFrequencies(documents[i], documents)
+			sparseB, _ := SparseTermFrequencies(documents[j], documents)
+
+			denseA := sparseA.Dense(len(vocabulary))
+			denseB := sparseB.Dense(len(vocabulary))
+
+			sparse := SparseCosineSimilarity(sparseA, sparseB)
+			dense := DenseCosineSimilarity(denseA, denseB)
+
+			if !almostEqual(sparse, dense) {
+				t.Errorf("documents %d and %d: sparse similarity %v, dense %v", i, j, sparse, dense)
+			}
+		}
+	}
+
+	// Shorter dense vectors are padded with zeros.
+	if got := DenseCosineSimilarity([]float64{1.0, 1.0}, []float64{1.0, 1.0, 0.0}); !almostEqual(got, 1.0) {
+		t.Errorf("DenseCosineSimilarity of padded vectors = %v, want 1", got)
+	}
+}
@@ -0,0 +1,90 @@
+package tfidf
+
+import (
+	"sync"
+
+	"github.com/blevesearch/go-porterstemmer"
+)
+
+// Structs and types
+
+// Reduces a single term to its stem. A Tokenizer passes terms in the
+// case it produced them in, i.e. lowercased unless it keeps their case,
+// see WithCaseSensitivity, and uses the returned stem as is. Thus, a
+// Stemmer changing the case of terms yields terms in that case. Implement
+// it to plug a stemmer for another language into a Tokenizer, see
+// WithStemmer, and prepare query terms via Tokenizer.StemTerm.
+type Stemmer interface {
+	Stem(term string) string
+}
+
+// The English Porter stemmer, used by default.
+type PorterStemmer struct{}
+
+// A stemmer leaving all terms untouched, e.g. for
+// languages without stemming support.
+type NoopStemmer struct{}
+
+// A Stemmer memoizing the results of another one, which pays off when
+// the same words are stemmed over and over again across documents. The
+// cache is guarded by a mutex, thus a CachedStemmer is safe for
+// concurrent use as long as the wrapped Stemmer is. Create one via
+// NewCachedStemmer or let a Tokenizer do it, see WithStemCache.
+type CachedStemmer struct {
+	stemmer Stemmer
+	maxSize int
+	lock    sync.Mutex
+	cache   map[string]string
+}
+
+// Functions
+
+// Wraps stemmer into a CachedStemmer holding at most maxSize terms.
+// Once the cache is full, further terms are stemmed but not cached,
+// so tokenizing unbounded input never grows it beyond maxSize.
+// maxSize <= 0 leaves the cache unbounded.
+func NewCachedStemmer(stemmer Stemmer, maxSize int) *CachedStemmer {
+
+	return &CachedStemmer{
+		stemmer: stemmer,
+		maxSize: maxSize,
+		cache:   make(map[string]string),
+	}
+}
+
+// Stems term with the Porter stemming algorithm, keeping its case.
+// The algorithm's rules only apply to lowercase letters, thus tokens
+// that are mostly uppercase, e.g. acronyms, are left untouched.
+func (PorterStemmer) Stem(term string) string {
+	return string(porterstemmer.StemWithoutLowerCasing([]rune(term)))
+}
+
+// Returns term as is.
+func (NoopStemmer) Stem(term string) string {
+	return term
+}
+
+// Returns the cached stem of term, stemming and
+// caching it with the wrapped Stemmer on a miss.
+func (s *CachedStemmer) Stem(term string) string {
+
+	s.lock.Lock()
+	stem, exists := s.cache[term]
+	s.lock.Unlock()
+
+	if exists {
+		return stem
+	}
+
+	// Stem outside of the lock, other goroutines
+	// stemming the same term produce the same result.
+	stem = s.stemmer.Stem(term)
+
+	s.lock.Lock()
+	if s.maxSize <= 0 || len(s.cache) < s.maxSize {
+		s.cache[term] = stem
+	}
+	s.lock.Unlock()
+
+	return stem
+}
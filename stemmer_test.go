@@ -0,0 +1,179 @@
+package tfidf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Counts the calls to a wrapped Stemmer.
+type countingStemmer struct {
+	calls int
+}
+
+func (s *countingStemmer) Stem(term string) string {
+
+	s.calls++
+
+	return PorterStemmer{}.Stem(term)
+}
+
+// Inflected English words for stemming to work on.
+var inflectedWords = []string{
+	"running", "connections", "policies", "generalizations", "happily",
+	"relational", "conditional", "rationalization", "hopefulness",
+	"formalities", "sensitivity", "electrical", "adjustment", "dependent",
+	"communication", "operating", "organizations", "activated", "routinely",
+	"effectiveness",
+}
+
+// Generates reproducible plain text documents by replacing every term
+// of a synthetic corpus with one of the inflected words.
+func syntheticText(numDocs int, docLength int) []string {
+
+	documents := syntheticCorpus(numDocs, docLength, len(inflectedWords))
+
+	texts := make([]string, len(documents))
+	for i, document := range documents {
+
+		words := make([]string, len(document))
+		for j, term := range document {
+
+			var index int
+			fmt.Sscanf(term, "term%d", &index)
+			words[j] = inflectedWords[index]
+		}
+
+		texts[i] = strings.Join(words, " ")
+	}
+
+	return texts
+}
+
+func TestStemCacheKeepsTokens(t *testing.T) {
+
+	documents := []string{
+		"Running runners ran to the running race",
+		"Connected connections connecting connectors connect",
+		"Running runners ran to the running race",
+	}
+
+	tests := []struct {
+		name   string
+		cached *Tokenizer
+		plain  *Tokenizer
+	}{
+		{"large", NewTokenizer(WithStemCache(1000)), NewTokenizer()},
+		{"bounded", NewTokenizer(WithStemCache(2)), NewTokenizer()},
+		{"case kept", NewTokenizer(WithStemCache(4), WithCaseSensitivity(true)), NewTokenizer(WithCaseSensitivity(true))},
+	}
+
+	for _, test := range tests {
+
+		for _, document := range documents {
+
+			if got, want := test.cached.Tokenize(document), test.plain.Tokenize(document); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, want)
+			}
+		}
+	}
+}
+
+func TestCachedStemmer(t *testing.T) {
+
+	counter := &countingStemmer{}
+	stemmer := NewCachedStemmer(counter, 2)
+
+	for _, term := range []string{"running", "running", "connected", "running", "connected"} {
+
+		if got, want := stemmer.Stem(term), (PorterStemmer{}).Stem(term); got != want {
+			t.Errorf("Stem(%s) = %s, want %s", term, got, want)
+		}
+	}
+
+	if counter.calls != 2 {
+		t.Errorf("wrapped stemmer called %d times, want 2", counter.calls)
+	}
+
+	// Terms beyond maxSize are stemmed on every call.
+	stemmer.Stem("policy")
+	stemmer.Stem("policy")
+
+	if counter.calls != 4 {
+		t.Errorf("wrapped stemmer called %d times, want 4", counter.calls)
+	}
+
+	if len(stemmer.cache) != 2 {
+		t.Errorf("cache holds %d terms, want 2", len(stemmer.cache))
+	}
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+
+	documents := syntheticText(100, 100)
+	tok := NewTokenizer()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+
+		for _, document := range documents {
+			tok.Tokenize(document)
+		}
+	}
+}
+
+func BenchmarkTokenizerStemCache(b *testing.B) {
+
+	documents := syntheticText(100, 100)
+	tok := NewTokenizer(WithStemCache(len(inflectedWords)))
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+
+		for _, document := range documents {
+			tok.Tokenize(document)
+		}
+	}
+}
+
+// Marks every term it stems by uppercasing it.
+type upperStemmer struct{}
+
+func (upperStemmer) Stem(term string) string {
+	return strings.ToUpper(term)
+}
+
+func TestWithStemmer(t *testing.T) {
+
+	document := "The running dogs"
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"custom stemmer", NewTokenizer(WithStemmer(upperStemmer{})), []string{"RUNNING", "DOGS"}},
+		{"custom stemmer cached", NewTokenizer(WithStemmer(upperStemmer{}), WithStemCache(10)), []string{"RUNNING", "DOGS"}},
+		{"custom stemmer disabled", NewTokenizer(WithStemmer(upperStemmer{}), WithStemming(false)), []string{"running", "dogs"}},
+		{"nil stemmer", NewTokenizer(WithStemmer(nil)), []string{"running", "dogs"}},
+		{"noop stemmer", NewTokenizer(WithStemmer(NoopStemmer{})), []string{"running", "dogs"}},
+		{"porter stemmer", NewTokenizer(WithStemmer(PorterStemmer{})), []string{"run", "dog"}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+
+	// The stemmed tokens flow through to scoring.
+	tok := NewTokenizer(WithStemmer(upperStemmer{}))
+	documents := [][]string{tok.Tokenize(document), tok.Tokenize("Sleeping cats")}
+
+	if got := TfIdf(documents[0], documents, TermWeightingRaw, InvDocWeightingLog)["DOGS"]; !almostEqual(got, math.Log(2.0)) {
+		t.Errorf("TfIdf[DOGS] = %v, want %v", got, math.Log(2.0))
+	}
+}
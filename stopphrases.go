@@ -0,0 +1,90 @@
+package tfidf
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Functions
+
+// Tokenizes the supplied document like TokenizeDocument but first drops
+// every occurrence of one of the supplied stop phrases, see WithStopPhrases.
+func TokenizeDocumentWithStopPhrases(document string, stopPhrases []string) []string {
+	return NewTokenizer(WithStopPhrases(stopPhrases)).Tokenize(document)
+}
+
+// Splits all stop phrases into raw token sequences the way tok splits
+// documents, longest phrases first. Phrases without tokens are dropped.
+func splitStopPhrases(tok *Tokenizer, stopPhrases []string) [][][]byte {
+
+	phrases := make([][][]byte, 0, len(stopPhrases))
+	for _, stopPhrase := range stopPhrases {
+
+		if phrase := tok.split(stopPhrase); len(phrase) > 0 {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	// Try longer phrases first.
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return len(phrases[i]) > len(phrases[j])
+	})
+
+	return phrases
+}
+
+// Drops all occurrences of the supplied token sequences from tokens and
+// returns the remaining segments between them. Without any occurrence,
+// the only segment holds all tokens.
+func removeStopPhrases(tokens [][]byte, phrases [][][]byte) [][][]byte {
+
+	if len(phrases) == 0 {
+		return [][][]byte{tokens}
+	}
+
+	segments := make([][][]byte, 0, 1)
+	segment := make([][]byte, 0, len(tokens))
+
+	for i := 0; i < len(tokens); {
+
+		matched := 0
+
+		// Check each phrase at the current position.
+		for _, phrase := range phrases {
+
+			if hasPhraseAt(tokens, i, phrase) {
+				matched = len(phrase)
+				break
+			}
+		}
+
+		if matched > 0 {
+			// Skip over the whole phrase and start a new segment.
+			i += matched
+			segments = append(segments, segment)
+			segment = make([][]byte, 0, len(tokens)-i)
+		} else {
+			segment = append(segment, tokens[i])
+			i++
+		}
+	}
+
+	return append(segments, segment)
+}
+
+// Reports whether phrase occurs in tokens starting at position i.
+func hasPhraseAt(tokens [][]byte, i int, phrase [][]byte) bool {
+
+	if i+len(phrase) > len(tokens) {
+		return false
+	}
+
+	for j, word := range phrase {
+
+		if !bytes.Equal(tokens[i+j], word) {
+			return false
+		}
+	}
+
+	return true
+}
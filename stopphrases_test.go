@@ -0,0 +1,54 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithStopPhrases(t *testing.T) {
+
+	// Keep all other tokens as they are to follow the phrases.
+	plain := []Option{WithStopWords(nil), WithStemming(false)}
+
+	tests := []struct {
+		name     string
+		phrases  []string
+		opts     []Option
+		document string
+		want     []string
+	}{
+		{"overlap taken from the left", []string{"terms and conditions", "and conditions apply"}, plain, "terms and conditions apply", []string{"apply"}},
+		{"overlap taken where it starts", []string{"terms and conditions", "and conditions apply"}, plain, "some and conditions apply", []string{"some"}},
+		{"longest phrase first", []string{"fine", "fine print"}, plain, "the fine print", []string{"the"}},
+		{"phrase at end of stream", []string{"fine print"}, plain, "read the fine print", []string{"read", "the"}},
+		{"phrase cut off at end of stream", []string{"fine print"}, plain, "read the fine", []string{"read", "the", "fine"}},
+		{"repeated phrase", []string{"fine print"}, plain, "fine print and fine print", []string{"and"}},
+		{"phrases lowercased", []string{"Fine Print"}, plain, "FINE print here", []string{"here"}},
+		{"case kept", []string{"Fine Print"}, append(plain, WithCaseSensitivity(true)), "Fine Print fine print", []string{"fine", "print"}},
+		{"matched before stemming", []string{"running shoes"}, nil, "running shoes run", []string{"run"}},
+		{"matched before stop words", []string{"the end"}, nil, "the end of the story", []string{"stori"}},
+		{"n-grams around phrase", []string{"terms and conditions"}, append(plain, WithNGrams(2)), "you accept terms and conditions today please", []string{"you accept", "today please"}},
+		{"no phrases", nil, plain, "terms and conditions", []string{"terms", "and", "conditions"}},
+	}
+
+	for _, test := range tests {
+
+		tok := NewTokenizer(append(test.opts, WithStopPhrases(test.phrases))...)
+		if got := tok.Tokenize(test.document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, test.document, got, test.want)
+		}
+	}
+}
+
+func TestTokenizeDocumentWithStopPhrases(t *testing.T) {
+
+	document := "Please read the terms and conditions before running"
+
+	if got, want := TokenizeDocumentWithStopPhrases(document, []string{"terms and conditions"}), []string{"pleas", "read", "run"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeDocumentWithStopPhrases = %q, want %q", got, want)
+	}
+
+	if got, want := TokenizeDocumentWithStopPhrases(document, nil), TokenizeDocument(document); !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeDocumentWithStopPhrases without phrases = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package tfidf
+
+// Functions
+
+// Tokenizes all supplied raw documents with TokenizeDocument.
+func TokenizeDocuments(documents []string) [][]string {
+
+	tokenized := make([][]string, len(documents))
+
+	for i, document := range documents {
+		tokenized[i] = TokenizeDocument(document)
+	}
+
+	return tokenized
+}
+
+// Runs a single raw term through the default tokenization pipeline so
+// it matches tokens of documents tokenized by TokenizeDocument. Reports
+// false if term does not result in exactly one token, e.g. because it
+// is a stop word or consists of several words.
+func tokenizeTerm(term string) (string, bool) {
+
+	tokens := TokenizeDocument(term)
+	if len(tokens) != 1 {
+		return "", false
+	}
+
+	return tokens[0], true
+}
+
+// Works like TermFrequency but takes a raw term and a raw document and
+// tokenizes both with the default pipeline first. Terms that do not
+// tokenize into exactly one token (stop words, several words) have a
+// frequency of 0.0.
+func TermFrequencyString(term string, document string, weighting TermWeighting) float64 {
+
+	token, ok := tokenizeTerm(term)
+	if !ok {
+		return 0.0
+	}
+
+	return TermFrequency(token, false, TokenizeDocument(document), weighting)
+}
+
+// Works like InverseDocumentFrequency but takes a raw term and raw
+// documents and tokenizes them with the default pipeline first. Terms
+// that do not tokenize into exactly one token are treated as not
+// occurring in any document.
+func InverseDocumentFrequencyString(term string, documents []string, weighting InvDocWeighting) float64 {
+
+	token, ok := tokenizeTerm(term)
+	if !ok {
+		// The empty string never occurs in a tokenized
+		// document, thus the term counts as absent.
+		token = ""
+	}
+
+	return InverseDocumentFrequency(token, false, TokenizeDocuments(documents), weighting)
+}
+
+// Works like InverseDocumentFrequencies but takes raw documents
+// and tokenizes them with the default pipeline first.
+func InverseDocumentFrequenciesStrings(documents []string, weighting InvDocWeighting) map[string]float64 {
+	return InverseDocumentFrequencies(TokenizeDocuments(documents), weighting)
+}
+
+// Works like TfIdf but takes a raw compareDoc and raw documents
+// and tokenizes them with the default pipeline first.
+func TfIdfStrings(compareDoc string, documents []string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) map[string]float64 {
+	return TfIdf(TokenizeDocument(compareDoc), TokenizeDocuments(documents), tfWeighting, idfWeighting)
+}
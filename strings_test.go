@@ -0,0 +1,59 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringWrappersMatchTokenizedFunctions(t *testing.T) {
+
+	raw := []string{
+		"The runners were running through the park",
+		"A runner ran home",
+		"Dogs bark in the park",
+	}
+	documents := TokenizeDocuments(raw)
+
+	for i, document := range raw {
+
+		if got, want := documents[i], TokenizeDocument(document); !reflect.DeepEqual(got, want) {
+			t.Errorf("TokenizeDocuments[%d] = %q, want %q", i, got, want)
+		}
+	}
+
+	for _, term := range []string{"Running", "park", "cats"} {
+
+		if got, want := TermFrequencyString(term, raw[0], TermWeightingRaw), TermFrequency(term, true, documents[0], TermWeightingRaw); !almostEqual(got, want) {
+			t.Errorf("TermFrequencyString(%s) = %v, want %v", term, got, want)
+		}
+
+		if got, want := InverseDocumentFrequencyString(term, raw, InvDocWeightingLogSmooth), InverseDocumentFrequency(term, true, documents, InvDocWeightingLogSmooth); !almostEqual(got, want) {
+			t.Errorf("InverseDocumentFrequencyString(%s) = %v, want %v", term, got, want)
+		}
+	}
+
+	if got, want := InverseDocumentFrequenciesStrings(raw, InvDocWeightingLog), InverseDocumentFrequencies(documents, InvDocWeightingLog); !reflect.DeepEqual(got, want) {
+		t.Errorf("InverseDocumentFrequenciesStrings = %v, want %v", got, want)
+	}
+
+	if got, want := TfIdfStrings(raw[1], raw, TermWeightingLog, InvDocWeightingLog), TfIdf(documents[1], documents, TermWeightingLog, InvDocWeightingLog); !reflect.DeepEqual(got, want) {
+		t.Errorf("TfIdfStrings = %v, want %v", got, want)
+	}
+}
+
+func TestStringWrappersRejectNonTerms(t *testing.T) {
+
+	raw := []string{"the park", "a park bench"}
+
+	// Stop words and multiple words are no single term.
+	for _, term := range []string{"the", "park bench", ""} {
+
+		if got := TermFrequencyString(term, raw[1], TermWeightingRaw); got != 0.0 {
+			t.Errorf("TermFrequencyString(%q) = %v, want 0", term, got)
+		}
+
+		if got, want := InverseDocumentFrequencyString(term, raw, InvDocWeightingLogMax), InverseDocumentFrequency("", false, TokenizeDocuments(raw), InvDocWeightingLogMax); got != want {
+			t.Errorf("InverseDocumentFrequencyString(%q) = %v, want %v", term, got, want)
+		}
+	}
+}
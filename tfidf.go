@@ -1,17 +1,25 @@
 package tfidf
 
 import (
-	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/blevesearch/go-porterstemmer"
-	"github.com/lytics/multibayes"
 )
 
 // Structs and types
 
-type weightingScheme int
+// Weighting scheme applied to raw term frequencies.
+// Use one of the TermWeighting* constants.
+type TermWeighting int
+
+// Weighting scheme applied to document frequencies.
+// Use one of the InvDocWeighting* constants.
+type InvDocWeighting int
 
 // Constants
 
@@ -21,36 +29,118 @@ const (
 
 	// Term frequency weightings:
 	// * Binary weighting.
-	TermWeightingBinary weightingScheme = 0
+	TermWeightingBinary TermWeighting = 0
 	// * Raw frequency weighting.
-	TermWeightingRaw weightingScheme = 1
-	// * Log normalization weighting.
-	TermWeightingLog weightingScheme = 2
+	TermWeightingRaw TermWeighting = 1
+	// * Log normalization weighting, i.e. sublinear scaling, see SublinearTF.
+	TermWeightingLog TermWeighting = 2
 	// * Double normalization 0.5 weighting.
-	TermWeightingDoubleHalf weightingScheme = 3
+	TermWeightingDoubleHalf TermWeighting = 3
 	// * Double normalization K weighting.
-	TermWeightingDoubleK weightingScheme = 4
+	TermWeightingDoubleK TermWeighting = 4
 
 	// Inverse document frequency weightings:
 	// * Unary weighting.
-	InvDocWeightingUnary weightingScheme = 0
+	InvDocWeightingUnary InvDocWeighting = 0
 	// * Log weighting.
-	InvDocWeightingLog weightingScheme = 1
+	InvDocWeightingLog InvDocWeighting = 1
 	// * Log smooth weighting.
-	InvDocWeightingLogSmooth weightingScheme = 2
+	InvDocWeightingLogSmooth InvDocWeighting = 2
 	// * Log maximum weighting.
-	InvDocWeightingLogMax weightingScheme = 3
-	// * Probabilistic weighting.
-	InvDocWeightingProb weightingScheme = 4
+	InvDocWeightingLogMax InvDocWeighting = 3
+	// * Probabilistic weighting, smoothed and clamped at 0.0.
+	InvDocWeightingProb InvDocWeighting = 4
+
+	// Default K used by TermWeightingDoubleK.
+	// Use TermFrequencyDoubleK to choose a different one.
+	DoubleNormalizationK float64 = 0.4
+
+	// Added to both counts of the odds InvDocWeightingProb
+	// is based on, the common 0.5 correction of IR models.
+	ProbSmoothing float64 = 0.5
+)
+
+var (
+	// Tokenizers used by TokenizeDocument and friends.
+	defaultTokenizer   = NewTokenizer()
+	unstemmedTokenizer = NewTokenizer(WithStemming(false))
 )
 
 var (
-	c = multibayes.NewClassifier()
-	t = c.Tokenizer
+	// Returned (wrapped) for weighting schemes outside
+	// of the defined constants, see Validate.
+	ErrUnknownWeighting = errors.New("tfidf: unknown weighting scheme")
+	// Returned (wrapped) for nil documents in a corpus,
+	// see ValidateDocuments.
+	ErrNilDocument = errors.New("tfidf: nil document")
 )
 
 // Functions
 
+// Returns an error wrapping ErrUnknownWeighting if w is not one
+// of the TermWeighting* constants, nil otherwise. Functions taking a
+// TermWeighting do not check it themselves and treat unknown schemes
+// like raw frequency weighting, see TermFrequencyChecked.
+func (w TermWeighting) Validate() error {
+
+	if w < TermWeightingBinary || w > TermWeightingDoubleK {
+		return fmt.Errorf("%w: term weighting %d", ErrUnknownWeighting, int(w))
+	}
+
+	return nil
+}
+
+// Returns an error wrapping ErrUnknownWeighting if w is not one
+// of the InvDocWeighting* constants, nil otherwise. Functions taking an
+// InvDocWeighting do not check it themselves and weigh all terms 0.0
+// for unknown schemes, see InverseDocumentFrequencyChecked.
+func (w InvDocWeighting) Validate() error {
+
+	if w < InvDocWeightingUnary || w > InvDocWeightingProb {
+		return fmt.Errorf("%w: inverse document weighting %d", ErrUnknownWeighting, int(w))
+	}
+
+	return nil
+}
+
+// Checks a corpus of tokenized documents for nil documents, e.g. ones
+// that failed to tokenize upstream, and returns an error wrapping
+// ErrNilDocument naming the index of the first one. All functions taking
+// a corpus consistently skip nil documents: they neither count towards
+// the number of documents nor contribute any term. Functions returning
+// one result per document keep an (empty or zero) entry for them, so
+// results stay aligned to the supplied documents. Empty but non-nil
+// documents, e.g. the tokenization of text consisting of stop words only,
+// are genuine documents and count. Call this first if nil documents
+// indicate a failure that should not go unnoticed.
+func ValidateDocuments(documents [][]string) error {
+
+	for i, document := range documents {
+
+		if document == nil {
+			return fmt.Errorf("%w at index %d", ErrNilDocument, i)
+		}
+	}
+
+	return nil
+}
+
+// Returns the number of documents of the corpus, skipping
+// nil documents as described at ValidateDocuments.
+func corpusSize(documents [][]string) int {
+
+	size := 0
+
+	for _, document := range documents {
+
+		if document != nil {
+			size++
+		}
+	}
+
+	return size
+}
+
 // Takes an input document in string representation
 // and tokenizes it. Along the way, stop bytes in the
 // document will be removed and each term left will only
@@ -60,58 +150,65 @@ var (
 // 'AddDocument' function from her 'tfidf' package:
 // https://github.com/allisonmorgan/tfidf/blob/master/tfidf.go#L36
 func TokenizeDocument(document string) []string {
+	return defaultTokenizer.Tokenize(document)
+}
 
-	// Reserve space for result list (tokenized document).
-	resultDocument := make([]string, 0)
-
-	// Tokenize the supplied document.
-	tokens := t.Tokenize([]byte(strings.ToLower(document)))
-
-	// Range over all produced tokens.
-	for _, token := range tokens {
-
-		// Boolean signal whether to include or exclude one token.
-		exclude := false
+// Tokenizes the supplied document like TokenizeDocument but only stems
+// the remaining terms if stem is set to true. Stop bytes are always
+// removed based on the lowercased, unstemmed token.
+func TokenizeDocumentWithOptions(document string, stem bool) []string {
 
-		// Range over all stop bytes from multibayes package
-		// and remove each from tokens list of input document.
-		for _, stopByte := range stopbytes {
+	if stem {
+		return defaultTokenizer.Tokenize(document)
+	}
 
-			if bytes.Equal(token.Term, stopByte) {
-				exclude = true
-				break
-			}
-		}
+	return unstemmedTokenizer.Tokenize(document)
+}
 
-		// Import iteration break: If token already considered,
-		// leave current iteration here.
-		if exclude {
-			continue
-		}
+// Tokenizes the supplied document like TokenizeDocument and
+// afterwards turns the resulting terms into n-grams of n terms,
+// see WithNGrams. n = 1 yields exactly what TokenizeDocument does.
+func TokenizeNGrams(document string, n int) []string {
+	return NewTokenizer(WithNGrams(n)).Tokenize(document)
+}
 
-		// Alright, token is a new one. Stem and add it to result list.
-		tokenStemmed := porterstemmer.StemString(string(token.Term))
-		resultDocument = append(resultDocument, tokenStemmed)
-	}
+// Tokenizes the supplied document exactly like TokenizeDocument but
+// additionally returns the length of the document before stop bytes
+// were removed. Pass this length to NormalizedTermFrequency if removed
+// stop words should still count towards document length.
+func TokenizeDocumentWithLength(document string) ([]string, int) {
+	return defaultTokenizer.tokenize(document)
+}
 
-	// Return the tokenized document. Might be of len() = 0.
-	return resultDocument
+// Lowercases and stems a single term exactly like the default
+// tokenization pipeline, i.e. TokenizeDocument, treats document tokens.
+// All functions taking a stem flag apply it to their term if the flag
+// is set and use the term as is otherwise, documents are never stemmed
+// by them. Thus, for a corpus tokenized and stemmed once up front, a
+// query term can be stemmed once via StemTerm as well and then passed
+// with stem set to false to skip stemming it again on every call.
+// StemTerm always lowercases and Porter stems, no matter how a custom
+// Tokenizer treats case or stems. For corpora tokenized by one, prepare
+// query terms via Tokenizer.StemTerm and pass stem set to false.
+func StemTerm(term string) string {
+	return porterstemmer.StemString(strings.ToLower(term))
 }
 
 // This function calculates the number of occurencies of a given
 // term in a given document. Based on the specified weighting scheme,
 // the result value will be in a specific form. This functions
 // expects a term, possibly stems it and looks up its frequency
-// in an already tokenized document.
-func TermFrequency(term string, stem bool, document []string, weighting weightingScheme) float64 {
+// in an already tokenized document. An empty term or document
+// results in a frequency of 0.0 for all weighting schemes.
+func TermFrequency(term string, stem bool, document []string, weighting TermWeighting) float64 {
 
 	// Set frequency to 0 initially.
 	var frequency float64
 	frequency = 0.0
 
 	if stem {
-		// Stem input term.
-		term = porterstemmer.StemString(term)
+		// Lowercase and stem input term like TokenizeDocument does.
+		term = StemTerm(term)
 	}
 
 	// Iterate over tokens in document.
@@ -124,28 +221,246 @@ func TermFrequency(term string, stem bool, document []string, weighting weightin
 		}
 	}
 
+	// Double normalization needs the frequency of
+	// the most frequent term in the document.
+	maxFrequency := 0.0
+	if weighting == TermWeightingDoubleHalf || weighting == TermWeightingDoubleK {
+		maxFrequency = MaxTermFrequency(document)
+	}
+
+	return weightTermFrequency(frequency, maxFrequency, weighting)
+}
+
+// Takes in a term, possibly stems it like TermFrequency does and
+// returns the zero-based positions at which it occurs in an already
+// tokenized document, in ascending order. Useful for proximity scoring
+// and snippet generation. An absent term yields an empty slice.
+func TermPositions(term string, stem bool, document []string) []int {
+
+	if stem {
+		// Lowercase and stem input term like TokenizeDocument does.
+		term = StemTerm(term)
+	}
+
+	positions := make([]int, 0)
+
+	// Iterate over tokens in document.
+	for i, token := range document {
+
+		if term == token {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+// Works like TermFrequency but validates the weighting scheme first
+// and returns an error wrapping ErrUnknownWeighting for unknown ones.
+func TermFrequencyChecked(term string, stem bool, document []string, weighting TermWeighting) (float64, error) {
+
+	if err := weighting.Validate(); err != nil {
+		return 0.0, err
+	}
+
+	return TermFrequency(term, stem, document, weighting), nil
+}
+
+// Computes the double normalization K term frequency of a term in
+// an already tokenized document, K + (1 - K) * (freq / maxFreq) with
+// maxFreq being the frequency of the most frequent term in the document.
+// Terms absent from the document have a frequency of 0.0.
+func TermFrequencyDoubleK(term string, stem bool, document []string, k float64) float64 {
+
+	raw := TermFrequency(term, stem, document, TermWeightingRaw)
+
+	return doubleNormalization(raw, MaxTermFrequency(document), k)
+}
+
+// Returns the number of occurencies of the most frequent term in an
+// already tokenized document, 0.0 for an empty one. This is the maximum
+// frequency the double normalization (augmented frequency) schemes
+// relate to, e.g. for computing them on precomputed raw frequencies.
+func MaxTermFrequency(document []string) float64 {
+	return maxCount(termCounts(document))
+}
+
+// Returns the highest count in the supplied term counts.
+func maxCount(counts map[string]float64) float64 {
+
+	highest := 0.0
+
+	for _, count := range counts {
+
+		if count > highest {
+			highest = count
+		}
+	}
+
+	return highest
+}
+
+// Applies double normalization K to a raw frequency. Only terms
+// present in the document are normalized, absent ones stay at 0.0.
+func doubleNormalization(frequency float64, maxFrequency float64, k float64) float64 {
+
+	if frequency == 0.0 || maxFrequency == 0.0 {
+		return 0.0
+	}
+
+	return k + (1.0-k)*(frequency/maxFrequency)
+}
+
+// Works like TermFrequency but divides the weighted frequency by
+// the supplied document length. This makes the length normalization
+// explicit: pass len(document) to normalize by the number of terms
+// left after stop byte removal, or the length returned by
+// TokenizeDocumentWithLength to let removed stop words count
+// towards the length as well. A length <= 0 falls back to
+// len(document). For an empty document 0.0 is returned.
+func NormalizedTermFrequency(term string, stem bool, document []string, length int, weighting TermWeighting) float64 {
+
+	if length <= 0 {
+		length = len(document)
+	}
+
+	if length == 0 {
+		return 0.0
+	}
+
+	return TermFrequency(term, stem, document, weighting) / float64(length)
+}
+
+// Applies the supplied term frequency weighting scheme
+// to a raw number of occurencies of a term in a document.
+// maxFrequency is the number of occurencies of the most
+// frequent term in that document and only needed by the
+// double normalization schemes.
+func weightTermFrequency(frequency float64, maxFrequency float64, weighting TermWeighting) float64 {
+
 	// Apply supplied weighting scheme.
 	switch weighting {
-	case TermWeightingLog:
+	case TermWeightingBinary:
 		if frequency != 0.0 {
-			// Apply log normalization.
-			frequency = 1.0 + math.Log(frequency)
+			// Only signal presence.
+			frequency = 1.0
 		}
+	case TermWeightingLog:
+		// Apply log normalization.
+		frequency = SublinearTF(frequency)
+	case TermWeightingDoubleHalf:
+		// Apply double normalization 0.5.
+		frequency = doubleNormalization(frequency, maxFrequency, 0.5)
+	case TermWeightingDoubleK:
+		// Apply double normalization with default K.
+		frequency = doubleNormalization(frequency, maxFrequency, DoubleNormalizationK)
 	}
 
 	return frequency
 }
 
+// Scales a raw term frequency sublinearly to 1 + log(frequency), the
+// scaling behind TermWeightingLog. This is the same as the sublinear_tf
+// option of scikit-learn, thus term frequencies of 1 stay 1 and e.g. 5
+// becomes 1 + ln(5). Non-integer frequencies, e.g. weighted counts, are
+// scaled the same way. Absent terms (frequency <= 0) yield 0.0 instead
+// of the -Inf of log(0).
+func SublinearTF(frequency float64) float64 {
+
+	if frequency <= 0.0 {
+		return 0.0
+	}
+
+	return 1.0 + math.Log(frequency)
+}
+
+// Takes in a batch of tokenized documents and a fixed vocabulary and
+// returns one term frequency vector per document in a single pass over
+// each document. Position i of every vector holds the weighted frequency
+// of vocabulary[i], so all vectors are aligned to the vocabulary.
+func BatchTermFrequencies(docs [][]string, vocabulary []string, weighting TermWeighting) [][]float64 {
+
+	// Reserve space for one vector per document.
+	frequencies := make([][]float64, len(docs))
+
+	// Range over all documents.
+	for i, doc := range docs {
+
+		// Count all tokens of this document once.
+		counts := termCounts(doc)
+		maxFrequency := maxCount(counts)
+
+		// Look up each vocabulary term in the counts.
+		vector := make([]float64, len(vocabulary))
+		for j, term := range vocabulary {
+			vector[j] = weightTermFrequency(counts[term], maxFrequency, weighting)
+		}
+
+		frequencies[i] = vector
+	}
+
+	return frequencies
+}
+
 // This function takes in a compareDocument for which it will
 // return the frequency of tokens in it. The number and order of
-// tokens will be obtained by the given documents corpora.
+// tokens will be obtained by the given documents corpora, i.e.
+// position i holds the frequency of Vocabulary(documents)[i].
 // Note that compareDoc usually is in the corpora and both lists
-// contain already tokenized elements.
+// contain already tokenized elements. Terms of compareDoc absent from
+// the corpus are not part of the vector, see TermFrequenciesUnion for
+// keeping them, e.g. for a fresh query. An empty corpus has no tokens,
+// thus an empty (non-nil) vector is returned for it. An empty
+// compareDoc results in a vector of zeros.
 func TermFrequencies(compareDoc []string, documents [][]string) []float64 {
 
-	// Initialize result frequency vector and appearance map.
-	frequencies := make([]float64, 0)
-	appearance := make(map[string]bool)
+	frequencies, _ := TermFrequenciesWithVocabulary(compareDoc, documents)
+
+	return frequencies
+}
+
+// Works like TermFrequencies but additionally returns the vocabulary the
+// frequency vector is aligned to: frequencies[i] is the raw frequency of
+// vocabulary[i] in compareDoc. The vocabulary is sorted lexicographically,
+// see Vocabulary, thus vectors of different documents computed against
+// the same corpus are directly comparable position by position.
+func TermFrequenciesWithVocabulary(compareDoc []string, documents [][]string) ([]float64, []string) {
+
+	vocabulary := Vocabulary(documents)
+
+	return BatchTermFrequencies([][]string{compareDoc}, vocabulary, TermWeightingRaw)[0], vocabulary
+}
+
+// Works like TermFrequenciesWithVocabulary but the vocabulary is the
+// union of the corpus terms and the terms of compareDoc, so terms of
+// compareDoc not present anywhere in the corpus are counted as well
+// instead of being dropped. Use this if compareDoc is not part of the
+// corpus, e.g. a fresh query. The vocabulary is sorted lexicographically.
+func TermFrequenciesUnion(compareDoc []string, documents [][]string) ([]float64, []string) {
+
+	// Extend the corpus by compareDoc without touching the supplied one.
+	extended := make([][]string, 0, len(documents)+1)
+	extended = append(extended, documents...)
+	extended = append(extended, compareDoc)
+
+	vocabulary := Vocabulary(extended)
+
+	return BatchTermFrequencies([][]string{compareDoc}, vocabulary, TermWeightingRaw)[0], vocabulary
+}
+
+// Works like TermFrequencies but returns a map from each term of the
+// corpus to its frequency in compareDoc, weighted by the supplied scheme,
+// so no alignment to a vocabulary is needed. Corpus terms absent from
+// compareDoc map to their weighted zero frequency, terms of compareDoc
+// not present in the corpus are left out. This is the term frequency
+// counterpart of InverseDocumentFrequencies.
+func TermFrequenciesMap(compareDoc []string, documents [][]string, weighting TermWeighting) map[string]float64 {
+
+	// Count all tokens of compareDoc once.
+	counts := termCounts(compareDoc)
+	maxFrequency := maxCount(counts)
+
+	frequencies := make(map[string]float64)
 
 	// Range over all documents.
 	for _, document := range documents {
@@ -154,42 +469,93 @@ func TermFrequencies(compareDoc []string, documents [][]string) []float64 {
 		for _, token := range document {
 
 			// Check if we already considered this token.
-			if exists := appearance[token]; !exists {
+			if _, exists := frequencies[token]; !exists {
+				frequencies[token] = weightTermFrequency(counts[token], maxFrequency, weighting)
+			}
+		}
+	}
 
-				// Add the frequency of the new token in compareDoc to vector.
-				frequencies = append(frequencies, TermFrequency(token, false, compareDoc, TermWeightingRaw))
+	return frequencies
+}
+
+// Returns the lexicographically sorted list of distinct terms in the
+// supplied corpus of tokenized documents, each term exactly once. This
+// is the vocabulary all vector representations of this package refer
+// to, so it may be inspected or cached by callers.
+func Vocabulary(documents [][]string) []string {
+
+	// Initialize result list and appearance map.
+	vocabulary := make([]string, 0)
+	appearance := make(map[string]bool)
+
+	// Range over all documents.
+	for _, document := range documents {
 
-				// Set visited value for this token to true.
+		// Range over all tokens in current document.
+		for _, token := range document {
+
+			// Check if we already considered this token.
+			if exists := appearance[token]; !exists {
+				vocabulary = append(vocabulary, token)
 				appearance[token] = true
 			}
 		}
 	}
 
-	return frequencies
+	sort.Strings(vocabulary)
+
+	return vocabulary
 }
 
 // Takes in a term, possibly stems it and counts its appearance
 // in the supplied set of already tokenized documents. The resulting
-// value will be altered by supplied weighting scheme.
-func InverseDocumentFrequency(term string, stem bool, documents [][]string, weighting weightingScheme) float64 {
+// value will be altered by supplied weighting scheme. For an empty
+// corpus the result is 0.0 for all schemes instead of an infinite value.
+// Nil documents are skipped, see ValidateDocuments.
+func InverseDocumentFrequency(term string, stem bool, documents [][]string, weighting InvDocWeighting) float64 {
+	return WeightedInverseDocumentFrequency(term, stem, documents, nil, weighting)
+}
 
-	// Declare result value.
-	var idf float64
+// Works like InverseDocumentFrequency but validates the weighting scheme
+// first and returns an error wrapping ErrUnknownWeighting for unknown ones.
+func InverseDocumentFrequencyChecked(term string, stem bool, documents [][]string, weighting InvDocWeighting) (float64, error) {
+
+	if err := weighting.Validate(); err != nil {
+		return 0.0, err
+	}
+
+	return InverseDocumentFrequency(term, stem, documents, weighting), nil
+}
+
+// Works like InverseDocumentFrequency but each document contributes
+// its weight instead of one to both the number of documents and the
+// number of documents containing the term. weights[i] belongs to
+// documents[i], documents without a weight (e.g. weights is nil)
+// count with a weight of 1.0.
+func WeightedInverseDocumentFrequency(term string, stem bool, documents [][]string, weights []float64, weighting InvDocWeighting) float64 {
 
 	if stem {
-		// Stem input term.
-		term = porterstemmer.StemString(term)
+		// Lowercase and stem input term like TokenizeDocument does.
+		term = StemTerm(term)
 	}
 
-	// Number of documents considered.
-	numDocs := len(documents)
+	// Weighted number of documents considered.
+	numDocs := 0.0
 
 	// Number of documents in which supplied term is present.
-	// To avoid a dision-by-zero, this term is initially set to zero.
-	numDocsWithTerm := 1.0
+	numDocsWithTerm := 0.0
 
 	// Range over all documents.
-	for _, document := range documents {
+	for d, document := range documents {
+
+		// Nil documents are not part of the corpus.
+		if document == nil {
+			continue
+		}
+
+		// Retrieve weight of current document.
+		weight := documentWeight(weights, d)
+		numDocs += weight
 
 		contained := false
 
@@ -197,18 +563,116 @@ func InverseDocumentFrequency(term string, stem bool, documents [][]string, weig
 		for i := 0; !contained && i < len(document); i++ {
 
 			// If the current document contains our stemmed term,
-			// increase the counter by one and leave the current document.
+			// increase the counter by its weight and leave the current document.
 			if term == document[i] {
-				numDocsWithTerm += 1.0
+				numDocsWithTerm += weight
 				contained = true
 			}
 		}
 	}
 
+	// Log maximum weighting relates to the most common term.
+	maxDocsWithTerm := 0.0
+	if weighting == InvDocWeightingLogMax {
+		maxDocsWithTerm = maxCount(weightedDocumentFrequencies(documents, weights))
+	}
+
+	return weightInverseDocumentFrequency(numDocs, numDocsWithTerm, maxDocsWithTerm, weighting)
+}
+
+// Sums up the weights of all non-nil documents, see documentWeight.
+// Without weights, this is the number of documents in the corpus.
+func weightedCorpusSize(documents [][]string, weights []float64) float64 {
+
+	numDocs := 0.0
+
+	for d, document := range documents {
+
+		if document != nil {
+			numDocs += documentWeight(weights, d)
+		}
+	}
+
+	return numDocs
+}
+
+// Counts the (possibly weighted) number of documents containing
+// each term of the corpus in a single pass over all documents.
+func weightedDocumentFrequencies(documents [][]string, weights []float64) map[string]float64 {
+
+	frequencies := make(map[string]float64)
+
+	// Range over all documents.
+	for d, document := range documents {
+
+		weight := documentWeight(weights, d)
+
+		// Terms already counted for the current document.
+		seen := make(map[string]bool)
+
+		for _, token := range document {
+
+			if !seen[token] {
+				frequencies[token] += weight
+				seen[token] = true
+			}
+		}
+	}
+
+	return frequencies
+}
+
+// Applies the supplied inverse document frequency weighting scheme to
+// the (possibly weighted) number of documents in a corpus, numDocs, and
+// the number of documents in it containing a term, numDocsWithTerm.
+// maxDocsWithTerm is the number of documents containing the most common
+// term of the corpus and only needed by log maximum weighting. Any
+// smoothing is part of the respective scheme, the counts are expected
+// to be exact. Terms not present in any document weigh 0.0 for all
+// schemes that would otherwise divide by zero. An empty corpus carries
+// no information at all, thus every term weighs 0.0 in it regardless
+// of the scheme.
+func weightInverseDocumentFrequency(numDocs float64, numDocsWithTerm float64, maxDocsWithTerm float64, weighting InvDocWeighting) float64 {
+
+	// Nothing to relate term to.
+	if numDocs <= 0.0 {
+		return 0.0
+	}
+
+	// Declare result value.
+	var idf float64
+
 	switch weighting {
+	case InvDocWeightingUnary:
+		// Every term weighs the same.
+		idf = 1.0
 	case InvDocWeightingLog:
-		// Apply log on quotient.
-		idf = math.Log(float64(numDocs) / numDocsWithTerm)
+		if numDocsWithTerm > 0.0 {
+			// Apply log on quotient.
+			idf = math.Log(numDocs / numDocsWithTerm)
+		}
+	case InvDocWeightingLogSmooth:
+		if numDocsWithTerm > 0.0 {
+			// Apply log on smoothed quotient.
+			idf = math.Log(1.0 + (numDocs / numDocsWithTerm))
+		}
+	case InvDocWeightingLogMax:
+		if maxDocsWithTerm > 0.0 {
+			// Apply log on quotient relative to most common term.
+			idf = math.Log(maxDocsWithTerm / (1.0 + numDocsWithTerm))
+		}
+	case InvDocWeightingProb:
+		if numDocsWithTerm > 0.0 {
+			// Apply log on smoothed odds of term being absent. Terms in
+			// at least half of all documents would weigh negative, down
+			// to -Inf for terms in every document, thus clamp them to 0.0.
+			// This also covers counts exceeding numDocs, where the odds
+			// turn negative and their log is NaN.
+			odds := (numDocs - numDocsWithTerm + ProbSmoothing) / (numDocsWithTerm + ProbSmoothing)
+			if odds > 1.0 {
+				idf = math.Log(odds)
+			}
+		}
 	}
 
 	return idf
@@ -216,30 +680,154 @@ func InverseDocumentFrequency(term string, stem bool, documents [][]string, weig
 
 // Wrapper function to retrieve the map[string]float64 representation
 // of an inverse document frequency vector for all terms in the supplied
-// corpus, e.g. all tokenized documents.
-func InverseDocumentFrequencies(documents [][]string, weighting weightingScheme) map[string]float64 {
+// corpus, e.g. all tokenized documents. The corpus is scanned once.
+func InverseDocumentFrequencies(documents [][]string, weighting InvDocWeighting) map[string]float64 {
+	return WeightedInverseDocumentFrequencies(documents, nil, weighting)
+}
 
-	// Initialize result and appearance map.
-	idfs := make(map[string]float64)
-	appearance := make(map[string]bool)
+// Works like InverseDocumentFrequencies but stops early once ctx is done,
+// e.g. because a deadline passed, returning ctx.Err() and a nil map. The
+// corpus is scanned once and ctx is checked before every document.
+func InverseDocumentFrequenciesContext(ctx context.Context, documents [][]string, weighting InvDocWeighting) (map[string]float64, error) {
+
+	// Count documents containing each term.
+	frequencies := make(map[string]float64)
 
 	// Range over all documents.
 	for _, document := range documents {
 
-		// Range over all tokens in current document.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Terms already counted for the current document.
+		seen := make(map[string]bool)
+
 		for _, token := range document {
 
-			// Check if we already considered this token.
-			if exists := appearance[token]; !exists {
+			if !seen[token] {
+				frequencies[token] += 1.0
+				seen[token] = true
+			}
+		}
+	}
 
-				// If we did not - add its idf value to the result map.
-				idfs[token] = InverseDocumentFrequency(token, false, documents, weighting)
+	// Log maximum weighting relates to the most common term.
+	maxDocsWithTerm := maxCount(frequencies)
 
-				// Set visited value for this token to true.
-				appearance[token] = true
+	idfs := make(map[string]float64, len(frequencies))
+	for term, frequency := range frequencies {
+		idfs[term] = weightInverseDocumentFrequency(float64(corpusSize(documents)), frequency, maxDocsWithTerm, weighting)
+	}
+
+	return idfs, nil
+}
+
+// Computes the tf-idf vector of compareDoc relative to the supplied
+// corpus of already tokenized documents. For each term of the corpus
+// vocabulary, the result holds the product of the term's frequency in
+// compareDoc and its inverse document frequency across the corpus,
+// weighted by the respective schemes. Vocabulary terms absent from
+// compareDoc are present with their (usually zero) weight, terms of
+// compareDoc outside the vocabulary are not.
+func TfIdf(compareDoc []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) map[string]float64 {
+	return TfIdfCombined(compareDoc, documents, tfWeighting, idfWeighting, MultiplyTfIdf)
+}
+
+// Takes in a term, possibly stems it and returns the plain number of
+// already tokenized documents containing it, without any smoothing.
+// Useful for building custom weighting schemes on top.
+func DocumentFrequency(term string, stem bool, documents [][]string) int {
+
+	if stem {
+		// Lowercase and stem input term like TokenizeDocument does.
+		term = StemTerm(term)
+	}
+
+	count := 0
+
+	// Range over all documents.
+	for _, document := range documents {
+
+		// Range over all tokens in document and leave
+		// the document as soon as the term was found.
+		for _, token := range document {
+
+			if term == token {
+				count++
+				break
 			}
 		}
 	}
 
+	return count
+}
+
+// Works like InverseDocumentFrequencies but min-max normalizes all
+// idf values across the vocabulary into the range [0, 1], the term
+// with the lowest idf mapping to 0.0 and the one with the highest to 1.0.
+// If all terms share the same idf, they are all mapped to 0.0. Besides
+// computing the idfs, this takes one pass over the vocabulary to find
+// both the minimum and the maximum and another one to scale all values.
+func NormalizedInverseDocumentFrequencies(documents [][]string, weighting InvDocWeighting) map[string]float64 {
+
+	idfs := InverseDocumentFrequencies(documents, weighting)
+
+	// Find minimum and maximum idf at once.
+	minIDF, maxIDF := math.Inf(1), math.Inf(-1)
+	for _, idf := range idfs {
+		minIDF = math.Min(minIDF, idf)
+		maxIDF = math.Max(maxIDF, idf)
+	}
+
+	// Without a range, no term is more informative than another.
+	if maxIDF <= minIDF {
+
+		for term := range idfs {
+			idfs[term] = 0.0
+		}
+
+		return idfs
+	}
+
+	// Scale all values into [0, 1].
+	for term, idf := range idfs {
+		idfs[term] = (idf - minIDF) / (maxIDF - minIDF)
+	}
+
 	return idfs
 }
+
+// Wrapper function to retrieve the map[string]float64 representation
+// of a weighted inverse document frequency vector for all terms in the
+// supplied corpus. See WeightedInverseDocumentFrequency for the weights.
+func WeightedInverseDocumentFrequencies(documents [][]string, weights []float64, weighting InvDocWeighting) map[string]float64 {
+
+	// Count documents containing each term and the most common
+	// term once for the whole corpus instead of once per term.
+	numDocs := weightedCorpusSize(documents, weights)
+	frequencies := weightedDocumentFrequencies(documents, weights)
+	maxDocsWithTerm := maxCount(frequencies)
+
+	idfs := make(map[string]float64, len(frequencies))
+	for term, frequency := range frequencies {
+		idfs[term] = weightInverseDocumentFrequency(numDocs, frequency, maxDocsWithTerm, weighting)
+	}
+
+	return idfs
+}
+
+// Computes the inverse document frequency of a term where each document's
+// contribution decays with its age, i.e. is multiplied by exp(-lambda * age).
+// ages[i] is the age of documents[i] in the unit lambda refers to,
+// see DocumentAges and TimeDecayWeights.
+func DecayedInverseDocumentFrequency(term string, stem bool, documents [][]string, ages []float64, lambda float64, weighting InvDocWeighting) float64 {
+	return WeightedInverseDocumentFrequency(term, stem, documents, TimeDecayWeights(ages, lambda), weighting)
+}
+
+// Wrapper function to retrieve the map[string]float64 representation of
+// the time decayed inverse document frequencies for all terms in the
+// supplied corpus. See DecayedInverseDocumentFrequency.
+func DecayedInverseDocumentFrequencies(documents [][]string, ages []float64, lambda float64, weighting InvDocWeighting) map[string]float64 {
+	return WeightedInverseDocumentFrequencies(documents, TimeDecayWeights(ages, lambda), weighting)
+}
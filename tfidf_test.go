@@ -0,0 +1,825 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Tolerance for comparing floating point results.
+const epsilon = 1e-12
+
+// Reports whether a and b are equal within epsilon.
+func almostEqual(a float64, b float64) bool {
+	return math.Abs(a-b) <= epsilon
+}
+
+func TestNilDocumentsAreSkipped(t *testing.T) {
+
+	clean := [][]string{{"a", "b"}, {}, {"b"}}
+	mixed := [][]string{{"a", "b"}, nil, {}, nil, {"b"}}
+
+	// Empty documents count, nil documents do not.
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		for _, term := range []string{"a", "b", "c"} {
+
+			got := InverseDocumentFrequency(term, false, mixed, weighting)
+			want := InverseDocumentFrequency(term, false, clean, weighting)
+
+			if !almostEqual(got, want) {
+				t.Errorf("weighting %d, term %q: idf with nil documents = %v, want %v", weighting, term, got, want)
+			}
+		}
+
+		if got, want := len(InverseDocumentFrequencies(mixed, weighting)), 2; got != want {
+			t.Errorf("weighting %d: %d idf values, want %d", weighting, got, want)
+		}
+	}
+
+	if got, want := BM25([]string{"a"}, mixed[0], mixed, BM25K1, BM25B), BM25([]string{"a"}, clean[0], clean, BM25K1, BM25B); !almostEqual(got, want) {
+		t.Errorf("BM25 with nil documents = %v, want %v", got, want)
+	}
+
+	if got, want := len(FilterVocabulary(mixed, 1, 0.5)), len(FilterVocabulary(clean, 1, 0.5)); got != want {
+		t.Errorf("FilterVocabulary with nil documents kept %d terms, want %d", got, want)
+	}
+
+	// Per-document results stay aligned.
+	influences := IDFInfluence(mixed, InvDocWeightingLog)
+	if len(influences) != len(mixed) || influences[1] != 0.0 || influences[3] != 0.0 {
+		t.Errorf("IDFInfluence with nil documents = %v", influences)
+	}
+
+	c := NewCorpus(mixed, InvDocWeightingLog)
+	if got, want := c.NumDocuments(), len(clean); got != want {
+		t.Errorf("Corpus.NumDocuments() = %d, want %d", got, want)
+	}
+
+	if id := c.AddDocument(nil); id != -1 {
+		t.Errorf("Corpus.AddDocument(nil) = %d, want -1", id)
+	}
+}
+
+func TestValidateDocuments(t *testing.T) {
+
+	if err := ValidateDocuments([][]string{{"a"}, {}}); err != nil {
+		t.Errorf("ValidateDocuments without nil documents = %v, want nil", err)
+	}
+
+	err := ValidateDocuments([][]string{{"a"}, {}, nil})
+	if !errors.Is(err, ErrNilDocument) {
+		t.Fatalf("ValidateDocuments = %v, want ErrNilDocument", err)
+	}
+
+	if want := "tfidf: nil document at index 2"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestTermFrequency(t *testing.T) {
+
+	document := []string{"a", "a", "a", "b"}
+
+	tests := []struct {
+		term      string
+		weighting TermWeighting
+		want      float64
+	}{
+		{"a", TermWeightingBinary, 1.0},
+		{"a", TermWeightingRaw, 3.0},
+		{"a", TermWeightingLog, 1.0 + math.Log(3.0)},
+		{"a", TermWeightingDoubleHalf, 1.0},
+		{"a", TermWeightingDoubleK, 1.0},
+		{"b", TermWeightingBinary, 1.0},
+		{"b", TermWeightingRaw, 1.0},
+		{"b", TermWeightingLog, 1.0},
+		{"b", TermWeightingDoubleHalf, 0.5 + 0.5/3.0},
+		{"b", TermWeightingDoubleK, 0.4 + 0.6/3.0},
+		{"c", TermWeightingBinary, 0.0},
+		{"c", TermWeightingRaw, 0.0},
+		{"c", TermWeightingLog, 0.0},
+		{"c", TermWeightingDoubleHalf, 0.0},
+		{"c", TermWeightingDoubleK, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := TermFrequency(test.term, false, document, test.weighting); !almostEqual(got, test.want) {
+			t.Errorf("TermFrequency(%s, %d) = %v, want %v", test.term, test.weighting, got, test.want)
+		}
+	}
+}
+
+func TestTermFrequencyDoubleK(t *testing.T) {
+
+	document := []string{"a", "a", "a", "b"}
+
+	tests := []struct {
+		term string
+		k    float64
+		want float64
+	}{
+		{"a", 0.2, 1.0},
+		{"b", 0.2, 0.2 + 0.8/3.0},
+		{"b", 0.5, TermFrequency("b", false, document, TermWeightingDoubleHalf)},
+		{"b", DoubleNormalizationK, TermFrequency("b", false, document, TermWeightingDoubleK)},
+		{"c", 0.2, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := TermFrequencyDoubleK(test.term, false, document, test.k); !almostEqual(got, test.want) {
+			t.Errorf("TermFrequencyDoubleK(%s, %v) = %v, want %v", test.term, test.k, got, test.want)
+		}
+	}
+}
+
+func TestInverseDocumentFrequency(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "c"},
+		{"a", "b"},
+		{"a"},
+		{"a", "a"},
+	}
+
+	tests := []struct {
+		term      string
+		weighting InvDocWeighting
+		want      float64
+	}{
+		{"a", InvDocWeightingUnary, 1.0},
+		{"b", InvDocWeightingUnary, 1.0},
+		{"z", InvDocWeightingUnary, 1.0},
+		{"a", InvDocWeightingLog, 0.0},
+		{"b", InvDocWeightingLog, math.Log(2.0)},
+		{"c", InvDocWeightingLog, math.Log(4.0)},
+		{"z", InvDocWeightingLog, 0.0},
+		{"a", InvDocWeightingLogSmooth, math.Log(2.0)},
+		{"b", InvDocWeightingLogSmooth, math.Log(3.0)},
+		{"c", InvDocWeightingLogSmooth, math.Log(5.0)},
+		{"z", InvDocWeightingLogSmooth, 0.0},
+		{"a", InvDocWeightingLogMax, math.Log(4.0 / 5.0)},
+		{"b", InvDocWeightingLogMax, math.Log(4.0 / 3.0)},
+		{"c", InvDocWeightingLogMax, math.Log(2.0)},
+		{"z", InvDocWeightingLogMax, math.Log(4.0)},
+		{"a", InvDocWeightingProb, 0.0},
+		{"b", InvDocWeightingProb, 0.0},
+		{"c", InvDocWeightingProb, math.Log(3.5 / 1.5)},
+		{"z", InvDocWeightingProb, 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := InverseDocumentFrequency(test.term, false, documents, test.weighting); !almostEqual(got, test.want) {
+			t.Errorf("InverseDocumentFrequency(%s, %d) = %v, want %v", test.term, test.weighting, got, test.want)
+		}
+	}
+}
+
+func TestInverseDocumentFrequenciesMatchPerTerm(t *testing.T) {
+
+	documents := syntheticCorpus(30, 10, 40)
+	documents[3] = nil
+	vocabulary := Vocabulary(documents)
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		idfs := InverseDocumentFrequencies(documents, weighting)
+		if len(idfs) != len(vocabulary) {
+			t.Fatalf("weighting %d: got idfs for %d terms, want %d", weighting, len(idfs), len(vocabulary))
+		}
+
+		for _, term := range vocabulary {
+
+			if want := InverseDocumentFrequency(term, false, documents, weighting); idfs[term] != want {
+				t.Errorf("weighting %d: idf(%s) = %v, want %v", weighting, term, idfs[term], want)
+			}
+		}
+	}
+}
+
+func TestWeightedInverseDocumentFrequenciesMatchPerTerm(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "c"},
+		{"a", "b"},
+		nil,
+		{"a"},
+		{"a", "a"},
+	}
+
+	// The last document has no weight and counts with 1.0.
+	weights := []float64{0.5, 2.0, 3.0, 1.0}
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		idfs := WeightedInverseDocumentFrequencies(documents, weights, weighting)
+		if len(idfs) != 3 {
+			t.Fatalf("weighting %d: got idfs for %d terms, want 3", weighting, len(idfs))
+		}
+
+		for term, idf := range idfs {
+
+			if want := WeightedInverseDocumentFrequency(term, false, documents, weights, weighting); !almostEqual(idf, want) {
+				t.Errorf("weighting %d: idf(%s) = %v, want %v", weighting, term, idf, want)
+			}
+		}
+	}
+}
+
+func TestTfIdf(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "sat", "mat"},
+		{"dog", "sat", "log"},
+		{"cat", "cat", "hat"},
+	}
+
+	// Raw frequency times log(3 / document frequency).
+	want := map[string]float64{
+		"cat": 2.0 * math.Log(3.0/2.0),
+		"dog": 0.0,
+		"hat": math.Log(3.0),
+		"log": 0.0,
+		"mat": 0.0,
+		"sat": 0.0,
+	}
+
+	got := TfIdf(documents[2], documents, TermWeightingRaw, InvDocWeightingLog)
+
+	if len(got) != len(want) {
+		t.Fatalf("TfIdf = %v, want %v", got, want)
+	}
+
+	for term, weight := range want {
+
+		if value, exists := got[term]; !exists || !almostEqual(value, weight) {
+			t.Errorf("TfIdf[%s] = %v, want %v", term, value, weight)
+		}
+	}
+
+	// Terms outside the corpus vocabulary are left out.
+	if _, exists := TfIdf([]string{"bird"}, documents, TermWeightingRaw, InvDocWeightingLog)["bird"]; exists {
+		t.Errorf("TfIdf holds term bird absent from the corpus")
+	}
+}
+
+func TestTokenizeDocumentWithOptions(t *testing.T) {
+
+	document := "Running runners ran"
+
+	tests := []struct {
+		stem bool
+		want []string
+	}{
+		{true, []string{"run", "runner", "ran"}},
+		{false, []string{"running", "runners", "ran"}},
+	}
+
+	for _, test := range tests {
+
+		if got := TokenizeDocumentWithOptions(document, test.stem); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("TokenizeDocumentWithOptions(%q, %t) = %q, want %q", document, test.stem, got, test.want)
+		}
+	}
+
+	if got, want := TokenizeDocumentWithOptions(document, true), TokenizeDocument(document); !reflect.DeepEqual(got, want) {
+		t.Errorf("stemmed tokens %q differ from TokenizeDocument %q", got, want)
+	}
+}
+
+func TestTermFrequenciesWithVocabulary(t *testing.T) {
+
+	documents := [][]string{
+		{"dog", "cat"},
+		{"bird", "cat", "cat"},
+		{"ant"},
+	}
+
+	wantVocabulary := []string{"ant", "bird", "cat", "dog"}
+
+	tests := []struct {
+		document []string
+		want     []float64
+	}{
+		{documents[0], []float64{0.0, 0.0, 1.0, 1.0}},
+		{documents[1], []float64{0.0, 1.0, 2.0, 0.0}},
+		{[]string{"ant", "fish"}, []float64{1.0, 0.0, 0.0, 0.0}},
+	}
+
+	for _, test := range tests {
+
+		frequencies, vocabulary := TermFrequenciesWithVocabulary(test.document, documents)
+
+		if !reflect.DeepEqual(vocabulary, wantVocabulary) {
+			t.Errorf("vocabulary for %q = %q, want %q", test.document, vocabulary, wantVocabulary)
+		}
+
+		if !reflect.DeepEqual(frequencies, test.want) {
+			t.Errorf("frequencies for %q = %v, want %v", test.document, frequencies, test.want)
+		}
+
+		if plain := TermFrequencies(test.document, documents); !reflect.DeepEqual(plain, frequencies) {
+			t.Errorf("TermFrequencies for %q = %v, want %v", test.document, plain, frequencies)
+		}
+	}
+
+	// Reordering the corpus keeps vectors aligned the same way.
+	reordered := [][]string{documents[2], documents[1], documents[0]}
+	if frequencies, vocabulary := TermFrequenciesWithVocabulary(documents[1], reordered); !reflect.DeepEqual(vocabulary, wantVocabulary) || !reflect.DeepEqual(frequencies, tests[1].want) {
+		t.Errorf("reordered corpus: frequencies %v over %q, want %v over %q", frequencies, vocabulary, tests[1].want, wantVocabulary)
+	}
+}
+
+func TestInverseDocumentFrequencyLogCountsAllDocuments(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "c"},
+		{"b", "c"},
+		{"c"},
+	}
+
+	tests := []struct {
+		term string
+		want float64
+	}{
+		{"a", math.Log(3.0)},
+		{"b", math.Log(3.0 / 2.0)},
+		{"c", 0.0},
+	}
+
+	for _, test := range tests {
+
+		if got := InverseDocumentFrequency(test.term, false, documents, InvDocWeightingLog); !almostEqual(got, test.want) {
+			t.Errorf("InverseDocumentFrequency(%s) = %v, want %v", test.term, got, test.want)
+		}
+
+		if got := InverseDocumentFrequencies(documents, InvDocWeightingLog)[test.term]; !almostEqual(got, test.want) {
+			t.Errorf("InverseDocumentFrequencies[%s] = %v, want %v", test.term, got, test.want)
+		}
+	}
+}
+
+func TestStemmedTermsAreLowercased(t *testing.T) {
+
+	documents := TokenizeDocuments([]string{"Running in Paris", "Paris is nice"})
+
+	for _, term := range []string{"Running", "RUNNING", "running"} {
+
+		if got := TermFrequency(term, true, documents[0], TermWeightingRaw); got != 1.0 {
+			t.Errorf("TermFrequency(%s) = %v, want 1", term, got)
+		}
+
+		if got, want := InverseDocumentFrequency(term, true, documents, InvDocWeightingLog), math.Log(2.0); !almostEqual(got, want) {
+			t.Errorf("InverseDocumentFrequency(%s) = %v, want %v", term, got, want)
+		}
+
+		if got, want := TermPositions(term, true, documents[0]), []int{0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("TermPositions(%s) = %v, want %v", term, got, want)
+		}
+	}
+
+	if got, want := StemTerm("Paris"), documents[1][0]; got != want {
+		t.Errorf("StemTerm(Paris) = %s, want %s", got, want)
+	}
+
+	// Unstemmed terms are looked up as they are.
+	if got := TermFrequency("Paris", false, documents[0], TermWeightingRaw); got != 0.0 {
+		t.Errorf("TermFrequency(Paris) without stemming = %v, want 0", got)
+	}
+}
+
+func TestDocumentFrequency(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "cat", "dog"},
+		{"cat"},
+		{"cat", "bird"},
+		nil,
+	}
+
+	tests := []struct {
+		term string
+		stem bool
+		want int
+	}{
+		{"fish", false, 0},
+		{"dog", false, 1},
+		{"bird", false, 1},
+		{"cat", false, 3},
+		{"Cats", true, 3},
+		{"Cats", false, 0},
+	}
+
+	for _, test := range tests {
+
+		if got := DocumentFrequency(test.term, test.stem, documents); got != test.want {
+			t.Errorf("DocumentFrequency(%s, %t) = %d, want %d", test.term, test.stem, got, test.want)
+		}
+	}
+}
+
+func TestTermFrequenciesMap(t *testing.T) {
+
+	documents := [][]string{
+		{"dog", "cat"},
+		{"bird", "cat", "cat"},
+	}
+	compareDoc := []string{"cat", "cat", "dog", "fish"}
+
+	tests := []struct {
+		weighting TermWeighting
+		want      map[string]float64
+	}{
+		{TermWeightingRaw, map[string]float64{"bird": 0.0, "cat": 2.0, "dog": 1.0}},
+		{TermWeightingBinary, map[string]float64{"bird": 0.0, "cat": 1.0, "dog": 1.0}},
+		{TermWeightingDoubleHalf, map[string]float64{"bird": 0.0, "cat": 1.0, "dog": 0.75}},
+	}
+
+	for _, test := range tests {
+
+		if got := TermFrequenciesMap(compareDoc, documents, test.weighting); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("TermFrequenciesMap(%d) = %v, want %v", test.weighting, got, test.want)
+		}
+	}
+
+	// Raw frequencies agree with the aligned vector.
+	frequencies, vocabulary := TermFrequenciesWithVocabulary(compareDoc, documents)
+	byTerm := TermFrequenciesMap(compareDoc, documents, TermWeightingRaw)
+
+	for i, term := range vocabulary {
+
+		if byTerm[term] != frequencies[i] {
+			t.Errorf("TermFrequenciesMap[%s] = %v, want %v", term, byTerm[term], frequencies[i])
+		}
+	}
+}
+
+func TestTermPositions(t *testing.T) {
+
+	document := []string{"run", "fast", "run", "slow", "run"}
+
+	tests := []struct {
+		term string
+		want []int
+	}{
+		{"run", []int{0, 2, 4}},
+		{"slow", []int{3}},
+		{"walk", []int{}},
+	}
+
+	for _, test := range tests {
+
+		if got := TermPositions(test.term, false, document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("TermPositions(%s) = %v, want %v", test.term, got, test.want)
+		}
+	}
+
+	// Query terms are stemmed to match stemmed documents.
+	if got, want := TermPositions("Running", true, document), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TermPositions(Running) = %v, want %v", got, want)
+	}
+
+	if got := TermPositions("run", false, nil); got == nil || len(got) != 0 {
+		t.Errorf("TermPositions in empty document = %#v, want empty slice", got)
+	}
+}
+
+func TestMaxTermFrequency(t *testing.T) {
+
+	tests := []struct {
+		document []string
+		want     float64
+	}{
+		{nil, 0.0},
+		{[]string{}, 0.0},
+		{[]string{"a", "b", "c"}, 1.0},
+		{[]string{"a", "b", "a", "c", "a", "b"}, 3.0},
+	}
+
+	for _, test := range tests {
+
+		if got := MaxTermFrequency(test.document); got != test.want {
+			t.Errorf("MaxTermFrequency(%q) = %v, want %v", test.document, got, test.want)
+		}
+	}
+
+	// Double normalization relates to it.
+	document := []string{"a", "b", "a", "c", "a", "b"}
+	raw := TermFrequency("b", false, document, TermWeightingRaw)
+
+	if got, want := TermFrequency("b", false, document, TermWeightingDoubleHalf), 0.5+0.5*raw/MaxTermFrequency(document); !almostEqual(got, want) {
+		t.Errorf("double normalization 0.5 = %v, want %v", got, want)
+	}
+}
+
+func TestInverseDocumentFrequenciesContext(t *testing.T) {
+
+	documents := [][]string{{"a", "b"}, {"b"}, nil, {"c"}}
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		idfs, err := InverseDocumentFrequenciesContext(context.Background(), documents, weighting)
+		if err != nil {
+			t.Fatalf("weighting %d: unexpected error %v", weighting, err)
+		}
+
+		if want := InverseDocumentFrequencies(documents, weighting); !reflect.DeepEqual(idfs, want) {
+			t.Errorf("weighting %d: idfs = %v, want %v", weighting, idfs, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if idfs, err := InverseDocumentFrequenciesContext(ctx, documents, InvDocWeightingLog); idfs != nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: idfs = %v, error %v, want nil, %v", idfs, err, context.Canceled)
+	}
+}
+
+func TestUnknownWeighting(t *testing.T) {
+
+	document := []string{"a", "b"}
+	documents := [][]string{document, {"b"}}
+
+	for _, weighting := range []TermWeighting{-1, TermWeightingDoubleK + 1, 42} {
+
+		if err := weighting.Validate(); !errors.Is(err, ErrUnknownWeighting) {
+			t.Errorf("TermWeighting(%d).Validate() = %v, want %v", weighting, err, ErrUnknownWeighting)
+		}
+
+		if _, err := TermFrequencyChecked("a", false, document, weighting); !errors.Is(err, ErrUnknownWeighting) {
+			t.Errorf("TermFrequencyChecked(%d) error = %v, want %v", weighting, err, ErrUnknownWeighting)
+		}
+	}
+
+	for _, weighting := range []InvDocWeighting{-1, InvDocWeightingProb + 1, 42} {
+
+		if err := weighting.Validate(); !errors.Is(err, ErrUnknownWeighting) {
+			t.Errorf("InvDocWeighting(%d).Validate() = %v, want %v", weighting, err, ErrUnknownWeighting)
+		}
+
+		if _, err := InverseDocumentFrequencyChecked("a", false, documents, weighting); !errors.Is(err, ErrUnknownWeighting) {
+			t.Errorf("InverseDocumentFrequencyChecked(%d) error = %v, want %v", weighting, err, ErrUnknownWeighting)
+		}
+	}
+
+	// Known schemes pass and compute like the unchecked functions.
+	for weighting := TermWeightingBinary; weighting <= TermWeightingDoubleK; weighting++ {
+
+		if got, err := TermFrequencyChecked("a", false, document, weighting); err != nil || got != TermFrequency("a", false, document, weighting) {
+			t.Errorf("TermFrequencyChecked(%d) = %v, %v", weighting, got, err)
+		}
+	}
+
+	for weighting := InvDocWeightingUnary; weighting <= InvDocWeightingProb; weighting++ {
+
+		if got, err := InverseDocumentFrequencyChecked("a", false, documents, weighting); err != nil || got != InverseDocumentFrequency("a", false, documents, weighting) {
+			t.Errorf("InverseDocumentFrequencyChecked(%d) = %v, %v", weighting, got, err)
+		}
+	}
+}
+
+func TestSublinearTF(t *testing.T) {
+
+	tests := []struct {
+		frequency float64
+		want      float64
+	}{
+		{-1.0, 0.0},
+		{0.0, 0.0},
+		{1.0, 1.0},
+		{5.0, 1.0 + math.Log(5.0)},
+		{2.5, 1.0 + math.Log(2.5)},
+	}
+
+	for _, test := range tests {
+
+		if got := SublinearTF(test.frequency); !almostEqual(got, test.want) {
+			t.Errorf("SublinearTF(%v) = %v, want %v", test.frequency, got, test.want)
+		}
+	}
+
+	// Log weighting applies it to the raw frequency.
+	document := []string{"a", "a", "a", "a", "a", "b"}
+	if got, want := TermFrequency("a", false, document, TermWeightingLog), SublinearTF(5.0); !almostEqual(got, want) {
+		t.Errorf("log weighted frequency = %v, want %v", got, want)
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+
+	tests := []struct {
+		documents [][]string
+		want      []string
+	}{
+		{nil, []string{}},
+		{[][]string{{}, nil}, []string{}},
+		{[][]string{{"b", "a", "b"}, {"c", "a"}, nil, {"a"}}, []string{"a", "b", "c"}},
+		{[][]string{{"Zebra", "apple", "zebra", "Apple"}}, []string{"Apple", "Zebra", "apple", "zebra"}},
+	}
+
+	for _, test := range tests {
+
+		if got := Vocabulary(test.documents); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Vocabulary(%q) = %q, want %q", test.documents, got, test.want)
+		}
+	}
+}
+
+func TestTermFrequenciesUnion(t *testing.T) {
+
+	documents := [][]string{
+		{"dog", "cat"},
+		{"bird", "cat"},
+	}
+	query := []string{"cat", "fish", "fish"}
+
+	union, unionVocabulary := TermFrequenciesUnion(query, documents)
+	corpus, corpusVocabulary := TermFrequenciesWithVocabulary(query, documents)
+
+	if want := []string{"bird", "cat", "dog", "fish"}; !reflect.DeepEqual(unionVocabulary, want) {
+		t.Errorf("union vocabulary = %q, want %q", unionVocabulary, want)
+	}
+
+	if want := []float64{0.0, 1.0, 0.0, 2.0}; !reflect.DeepEqual(union, want) {
+		t.Errorf("union frequencies = %v, want %v", union, want)
+	}
+
+	if want := []string{"bird", "cat", "dog"}; !reflect.DeepEqual(corpusVocabulary, want) {
+		t.Errorf("corpus vocabulary = %q, want %q", corpusVocabulary, want)
+	}
+
+	if want := []float64{0.0, 1.0, 0.0}; !reflect.DeepEqual(corpus, want) {
+		t.Errorf("corpus frequencies = %v, want %v", corpus, want)
+	}
+
+	// The supplied corpus is left untouched.
+	if len(documents) != 2 {
+		t.Errorf("corpus grew to %d documents", len(documents))
+	}
+
+	// Both agree for a document of the corpus.
+	if got, _ := TermFrequenciesUnion(documents[0], documents); !reflect.DeepEqual(got, TermFrequencies(documents[0], documents)) {
+		t.Errorf("union frequencies of corpus document = %v, want %v", got, TermFrequencies(documents[0], documents))
+	}
+}
+
+func TestInverseDocumentFrequencyProb(t *testing.T) {
+
+	documents := [][]string{
+		{"all", "half", "one"},
+		{"all", "half"},
+		{"all"},
+		{"all"},
+	}
+
+	tests := []struct {
+		term string
+		want float64
+	}{
+		{"all", 0.0},
+		{"half", 0.0},
+		{"one", math.Log((4.0 - 1.0 + ProbSmoothing) / (1.0 + ProbSmoothing))},
+		{"none", 0.0},
+	}
+
+	for _, test := range tests {
+
+		got := InverseDocumentFrequency(test.term, false, documents, InvDocWeightingProb)
+		if math.IsInf(got, 0) || math.IsNaN(got) || !almostEqual(got, test.want) {
+			t.Errorf("InverseDocumentFrequency(%s) = %v, want %v", test.term, got, test.want)
+		}
+	}
+
+	// A single document holding the term is smoothed instead of infinite.
+	if got := InverseDocumentFrequency("all", false, documents[2:3], InvDocWeightingProb); got != 0.0 {
+		t.Errorf("idf of term in the only document = %v, want 0", got)
+	}
+
+	// Counts exceeding the number of documents are clamped as well.
+	if got := weightInverseDocumentFrequency(1.0, 3.0, 3.0, InvDocWeightingProb); got != 0.0 {
+		t.Errorf("idf of inconsistent counts = %v, want 0", got)
+	}
+}
+
+func TestPreStemmedQueryTerms(t *testing.T) {
+
+	documents := TokenizeDocuments([]string{
+		"Generalizations about running are hard",
+		"Runners run",
+		"Policies",
+	})
+
+	for _, term := range []string{"Generalizations", "running", "POLICIES", "walking"} {
+
+		stemmed := StemTerm(term)
+
+		if got, want := TermFrequency(stemmed, false, documents[0], TermWeightingRaw), TermFrequency(term, true, documents[0], TermWeightingRaw); got != want {
+			t.Errorf("TermFrequency(%s) pre-stemmed = %v, want %v", term, got, want)
+		}
+
+		if got, want := InverseDocumentFrequency(stemmed, false, documents, InvDocWeightingLog), InverseDocumentFrequency(term, true, documents, InvDocWeightingLog); got != want {
+			t.Errorf("InverseDocumentFrequency(%s) pre-stemmed = %v, want %v", term, got, want)
+		}
+
+		if got, want := DocumentFrequency(stemmed, false, documents), DocumentFrequency(term, true, documents); got != want {
+			t.Errorf("DocumentFrequency(%s) pre-stemmed = %d, want %d", term, got, want)
+		}
+	}
+
+	// Stemmed query terms match the tokens of the corpus.
+	if got, want := DocumentFrequency(StemTerm("running"), false, documents), 2; got != want {
+		t.Errorf("DocumentFrequency(running) = %d, want %d", got, want)
+	}
+}
+
+func TestNormalizedInverseDocumentFrequencies(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		documents [][]string
+		weighting InvDocWeighting
+		want      map[string]float64
+	}{
+		// idf(a) = 0, idf(b) = log(3) and idf(c) = log(3 / 2).
+		{"scaled into range", [][]string{{"a", "b"}, {"a", "c"}, {"a", "c"}}, InvDocWeightingLog, map[string]float64{"a": 0.0, "b": 1.0, "c": math.Log(1.5) / math.Log(3.0)}},
+		{"equal idfs", [][]string{{"a"}, {"b"}}, InvDocWeightingLog, map[string]float64{"a": 0.0, "b": 0.0}},
+		{"unary idfs", [][]string{{"a", "b"}, {"c"}}, InvDocWeightingUnary, map[string]float64{"a": 0.0, "b": 0.0, "c": 0.0}},
+		{"empty corpus", nil, InvDocWeightingLog, map[string]float64{}},
+	}
+
+	for _, test := range tests {
+
+		got := NormalizedInverseDocumentFrequencies(test.documents, test.weighting)
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: got %d idfs, want %d", test.name, len(got), len(test.want))
+		}
+
+		for term, want := range test.want {
+
+			if !almostEqual(got[term], want) {
+				t.Errorf("%s: normalized idf(%s) = %v, want %v", test.name, term, got[term], want)
+			}
+		}
+	}
+}
+
+func TestTokenizeDocumentWithLength(t *testing.T) {
+
+	tokens, length := TokenizeDocumentWithLength("The cat and the dog chased the cat")
+
+	if want := []string{"cat", "dog", "chase", "cat"}; !reflect.DeepEqual(tokens, want) || length != 8 {
+		t.Fatalf("TokenizeDocumentWithLength = %q, %d, want %q, 8", tokens, length, want)
+	}
+
+	tests := []struct {
+		name   string
+		length int
+		want   float64
+	}{
+		{"length before stop word removal", length, 2.0 / 8.0},
+		{"length after stop word removal", len(tokens), 2.0 / 4.0},
+		{"zero length", 0, 2.0 / 4.0},
+		{"negative length", -3, 2.0 / 4.0},
+	}
+
+	for _, test := range tests {
+
+		if got := NormalizedTermFrequency("cat", false, tokens, test.length, TermWeightingRaw); !almostEqual(got, test.want) {
+			t.Errorf("%s: NormalizedTermFrequency = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	if tokens, length := TokenizeDocumentWithLength(""); len(tokens) != 0 || length != 0 {
+		t.Errorf("TokenizeDocumentWithLength of empty document = %q, %d, want no tokens, 0", tokens, length)
+	}
+}
+
+func TestTokenizeLengthWithMinTokenLength(t *testing.T) {
+
+	document := "go far ox away"
+
+	tests := []struct {
+		minLength int
+		want      []string
+	}{
+		// "far" has exactly the minimum length.
+		{3, []string{"far", "away"}},
+		{4, []string{"away"}},
+		{0, []string{"go", "far", "ox", "away"}},
+	}
+
+	for _, test := range tests {
+
+		// Dropped short tokens still count towards the length.
+		tokens, length := NewTokenizer(WithStopWords(nil), WithStemming(false), WithMinTokenLength(test.minLength)).tokenize(document)
+		if !reflect.DeepEqual(tokens, test.want) || length != 4 {
+			t.Errorf("minimum length %d: tokenize = %q, %d, want %q, 4", test.minLength, tokens, length, test.want)
+		}
+	}
+}
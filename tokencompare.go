@@ -0,0 +1,57 @@
+package tfidf
+
+import (
+	"sort"
+)
+
+// Structs and types
+
+// Any function turning a document into a list of tokens,
+// e.g. TokenizeDocument.
+type TokenizerFunc func(document string) []string
+
+// Functions
+
+// Runs two tokenizer configurations a and b on the same document and
+// returns a side-by-side diff of the resulting token sets: the tokens
+// produced only by a, only by b and by both. All three lists are
+// deduplicated and sorted lexicographically.
+func CompareTokenizations(document string, a TokenizerFunc, b TokenizerFunc) (onlyA []string, onlyB []string, both []string) {
+
+	// Build token sets of both configurations.
+	setA := make(map[string]bool)
+	for _, token := range a(document) {
+		setA[token] = true
+	}
+
+	setB := make(map[string]bool)
+	for _, token := range b(document) {
+		setB[token] = true
+	}
+
+	onlyA = make([]string, 0)
+	onlyB = make([]string, 0)
+	both = make([]string, 0)
+
+	for token := range setA {
+
+		if setB[token] {
+			both = append(both, token)
+		} else {
+			onlyA = append(onlyA, token)
+		}
+	}
+
+	for token := range setB {
+
+		if !setA[token] {
+			onlyB = append(onlyB, token)
+		}
+	}
+
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	sort.Strings(both)
+
+	return onlyA, onlyB, both
+}
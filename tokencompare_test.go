@@ -0,0 +1,45 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareTokenizations(t *testing.T) {
+
+	unstemmed := func(document string) []string {
+		return TokenizeDocumentWithOptions(document, false)
+	}
+
+	// Repeated tokens show up once, "runners" twice in both outputs.
+	document := "Runners running, runners run fast"
+
+	onlyA, onlyB, both := CompareTokenizations(document, TokenizeDocument, unstemmed)
+
+	if want := []string{"runner"}; !reflect.DeepEqual(onlyA, want) {
+		t.Errorf("onlyA = %q, want %q", onlyA, want)
+	}
+
+	if want := []string{"runners", "running"}; !reflect.DeepEqual(onlyB, want) {
+		t.Errorf("onlyB = %q, want %q", onlyB, want)
+	}
+
+	if want := []string{"fast", "run"}; !reflect.DeepEqual(both, want) {
+		t.Errorf("both = %q, want %q", both, want)
+	}
+
+	// Swapping the tokenizers swaps the exclusive sets.
+	swappedA, swappedB, swappedBoth := CompareTokenizations(document, unstemmed, TokenizeDocument)
+	if !reflect.DeepEqual(swappedA, onlyB) || !reflect.DeepEqual(swappedB, onlyA) || !reflect.DeepEqual(swappedBoth, both) {
+		t.Errorf("swapped comparison = %q, %q, %q", swappedA, swappedB, swappedBoth)
+	}
+
+	// Identical tokenizers share everything, an empty document nothing.
+	if onlyA, onlyB, both := CompareTokenizations(document, TokenizeDocument, TokenizeDocument); len(onlyA) != 0 || len(onlyB) != 0 || len(both) != 3 {
+		t.Errorf("identical comparison = %q, %q, %q", onlyA, onlyB, both)
+	}
+
+	if onlyA, onlyB, both := CompareTokenizations("", TokenizeDocument, unstemmed); onlyA == nil || len(onlyA)+len(onlyB)+len(both) != 0 {
+		t.Errorf("empty comparison = %q, %q, %q, want empty lists", onlyA, onlyB, both)
+	}
+}
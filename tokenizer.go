@@ -0,0 +1,429 @@
+package tfidf
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Constants
+
+const (
+
+	// Separator joining the terms of one n-gram.
+	NGramSeparator string = " "
+)
+
+// Structs and types
+
+// A configurable tokenization pipeline. Each Tokenizer owns its set
+// of stop words and decides whether and how remaining terms are
+// stemmed. Apart from its optional stem cache, which is guarded by a
+// mutex, a Tokenizer holds no mutable state after creation, thus one
+// instance may be used by many goroutines at the same time.
+// Create one via NewTokenizer, the zero value is not usable.
+type Tokenizer struct {
+	stopWords    map[string]bool
+	stem         bool
+	stemmer      Stemmer
+	stemCache    int
+	ngrams       int
+	normalize    bool
+	form         norm.Form
+	fold         bool
+	pattern      *regexp.Regexp
+	wordChars    string
+	keepCase     bool
+	minLength    int
+	stopPhrases  []string
+	phraseTokens [][][]byte
+}
+
+// Configures a Tokenizer on creation, see NewTokenizer.
+type Option func(*Tokenizer)
+
+// Functions
+
+// Replaces the default stop words (those of the multibayes
+// package) by the supplied list. Pass an empty list to keep
+// all tokens. Stop words are lowercased and matched against
+// unstemmed tokens, which are lowercased as well unless
+// case is kept, see WithCaseSensitivity.
+func WithStopWords(stopWords []string) Option {
+
+	return func(tok *Tokenizer) {
+
+		tok.stopWords = make(map[string]bool, len(stopWords))
+		for _, stopWord := range stopWords {
+			tok.stopWords[strings.ToLower(stopWord)] = true
+		}
+	}
+}
+
+// Enables or disables stemming of all terms that survived
+// stop word removal. Enabled by default, see WithStemmer.
+func WithStemming(stem bool) Option {
+
+	return func(tok *Tokenizer) {
+		tok.stem = stem
+	}
+}
+
+// Replaces the default PorterStemmer by the supplied Stemmer, e.g. one
+// for a different language. A nil stemmer leaves terms unstemmed.
+// Stemming itself still has to be enabled, which it is by default.
+func WithStemmer(stemmer Stemmer) Option {
+
+	return func(tok *Tokenizer) {
+
+		if stemmer == nil {
+			stemmer = NoopStemmer{}
+		}
+		tok.stemmer = stemmer
+	}
+}
+
+// Memoizes the results of the configured Stemmer in a cache of at most
+// maxSize terms, see CachedStemmer. The cache belongs to the Tokenizer
+// and lives as long as it does, thus it is disabled by default to not
+// keep terms of one-shot usage around. maxSize <= 0 disables it.
+func WithStemCache(maxSize int) Option {
+
+	return func(tok *Tokenizer) {
+		tok.stemCache = maxSize
+	}
+}
+
+// Makes the Tokenizer emit n-grams, i.e. all contiguous sequences of
+// n terms joined by NGramSeparator, instead of single terms. N-grams
+// are built at the very end of the pipeline, after stop word removal
+// and stemming, so "new york" is formed from the already filtered terms
+// and stop words never end up inside an n-gram. As a consequence, terms
+// that were separated only by stop words become adjacent. Documents with
+// less than n terms yield no n-grams. n <= 1 keeps single terms.
+func WithNGrams(n int) Option {
+
+	return func(tok *Tokenizer) {
+		tok.ngrams = n
+	}
+}
+
+// Normalizes documents to the supplied Unicode normalization form
+// (e.g. norm.NFC) before splitting them, so differently composed
+// forms of the same text yield the same tokens. Enabling it also
+// switches splitting to be Unicode aware: tokens are maximal runs
+// of letters, marks and digits of any script, instead of the ASCII
+// word characters kept by default. Consider disabling
+// stemming for non-English text, the Porter stemmer is English only.
+func WithUnicodeNormalization(form norm.Form) Option {
+
+	return func(tok *Tokenizer) {
+		tok.normalize = true
+		tok.form = form
+	}
+}
+
+// Enables or disables folding of accented characters into their
+// base characters, so "café" and "cafe" become the same token.
+// Folding removes all combining marks after canonical decomposition
+// and, like WithUnicodeNormalization, makes splitting Unicode aware.
+// Stop words are folded as well.
+func WithAccentFolding(fold bool) Option {
+
+	return func(tok *Tokenizer) {
+		tok.fold = fold
+	}
+}
+
+// Replaces splitting by a regular expression matching the tokens
+// themselves, e.g. `[a-z0-9]+(?:[-.][a-z0-9]+)*` to keep "covid-19"
+// and "v2.0" intact. Every non-overlapping match in the lowercased
+// (and possibly normalized) document becomes one raw token, everything
+// in between is dropped. Takes precedence over WithWordCharacters.
+// A nil pattern restores the default splitting.
+func WithTokenPattern(pattern *regexp.Regexp) Option {
+
+	return func(tok *Tokenizer) {
+		tok.pattern = pattern
+	}
+}
+
+// Treats all runes of characters as part of tokens in addition to the
+// default word characters, so e.g. "-." keeps "model-3" and "v2.0" as
+// single tokens instead of splitting them. Note that these characters
+// are kept anywhere, including at the start or end of a token.
+func WithWordCharacters(characters string) Option {
+
+	return func(tok *Tokenizer) {
+		tok.wordChars = characters
+	}
+}
+
+// Enables or disables case-sensitive tokenization. By default, all
+// documents are lowercased before splitting. With case kept, tokens
+// retain their original casing, so e.g. "US" and "us" or "WHO" and
+// "who" stay distinct terms. Stop words remain lowercase and are matched
+// exactly, thus only lowercase tokens are removed as stop words: "the"
+// is dropped while "The" is kept. Stemming keeps the case of tokens
+// as well, see PorterStemmer for how it treats uppercase letters.
+func WithCaseSensitivity(keepCase bool) Option {
+
+	return func(tok *Tokenizer) {
+		tok.keepCase = keepCase
+	}
+}
+
+// Drops all tokens shorter than n characters, e.g. single letters left
+// over from splitting possessives. The length is checked after stop word
+// removal and before stemming, thus it refers to the surface form of a
+// token and stems may end up shorter than n. n <= 1 keeps all tokens,
+// which is the default.
+func WithMinTokenLength(n int) Option {
+
+	return func(tok *Tokenizer) {
+		tok.minLength = n
+	}
+}
+
+// Drops every occurrence of one of the supplied stop phrases, e.g.
+// "terms and conditions", from documents. Stop phrases are split like
+// documents, thus they are lowercased unless case is kept, and matched
+// against the raw token stream, that is before stop word removal and
+// stemming. Overlapping occurrences are resolved from left to right,
+// preferring the longest phrase at each position. N-grams are built on
+// both sides of a removed phrase but never span it.
+func WithStopPhrases(stopPhrases []string) Option {
+
+	return func(tok *Tokenizer) {
+		tok.stopPhrases = stopPhrases
+	}
+}
+
+// Creates a new Tokenizer. Without any options it behaves exactly
+// like TokenizeDocument: lowercasing, removal of the default stop
+// words and Porter stemming.
+func NewTokenizer(opts ...Option) *Tokenizer {
+
+	tok := &Tokenizer{
+		stopWords: make(map[string]bool, len(stopbytes)),
+		stem:      true,
+		stemmer:   PorterStemmer{},
+	}
+
+	// Default to the stop bytes of the multibayes package.
+	for _, stopByte := range stopbytes {
+		tok.stopWords[string(stopByte)] = true
+	}
+
+	for _, opt := range opts {
+		opt(tok)
+	}
+
+	// Put the cache in front of whichever stemmer was configured.
+	if tok.stemCache > 0 {
+		tok.stemmer = NewCachedStemmer(tok.stemmer, tok.stemCache)
+	}
+
+	// Stop phrases have to be split like documents,
+	// which depends on all other options.
+	tok.phraseTokens = splitStopPhrases(tok, tok.stopPhrases)
+
+	// Stop words have to match normalized tokens.
+	if tok.unicodeAware() {
+
+		stopWords := make(map[string]bool, len(tok.stopWords))
+		for stopWord := range tok.stopWords {
+			stopWords[tok.normalizeText(stopWord)] = true
+		}
+		tok.stopWords = stopWords
+	}
+
+	return tok
+}
+
+// Takes an input document in string representation and tokenizes
+// it according to the configuration of the Tokenizer.
+func (tok *Tokenizer) Tokenize(document string) []string {
+
+	resultDocument, _ := tok.tokenize(document)
+
+	return resultDocument
+}
+
+// Prepares a single query term exactly like the Tokenizer treats document
+// tokens: it is normalized, lowercased unless case is kept and stemmed by
+// the configured Stemmer if stemming is enabled. Unlike Tokenize, the term
+// is neither split nor checked against stop words or the minimum length.
+// Pass the result with stem set to false to the functions taking a stem
+// flag, see StemTerm for the default pipeline.
+func (tok *Tokenizer) StemTerm(term string) string {
+
+	if tok.unicodeAware() {
+		term = tok.normalizeText(term)
+	}
+
+	if !tok.keepCase {
+		term = strings.ToLower(term)
+	}
+
+	if tok.stem {
+		term = tok.stemmer.Stem(term)
+	}
+
+	return term
+}
+
+// Tokenizes the supplied document and additionally returns
+// the number of tokens present before stop word removal.
+func (tok *Tokenizer) tokenize(document string) ([]string, int) {
+
+	resultDocument := make([]string, 0)
+	length := 0
+
+	// Removed stop phrases cut the token stream into segments,
+	// which are filtered and joined into n-grams one by one.
+	for _, segment := range removeStopPhrases(tok.split(document), tok.phraseTokens) {
+
+		terms, segmentLength := tok.filter(segment)
+		length += segmentLength
+
+		if tok.ngrams > 1 {
+			terms = buildNGrams(terms, tok.ngrams)
+		}
+
+		resultDocument = append(resultDocument, terms...)
+	}
+
+	return resultDocument, length
+}
+
+// Lowercases the supplied document unless case is kept and splits
+// it into its raw tokens, without any filtering or stemming.
+func (tok *Tokenizer) split(document string) [][]byte {
+
+	text := document
+	if tok.unicodeAware() {
+		text = tok.normalizeText(text)
+	}
+
+	if !tok.keepCase {
+		text = strings.ToLower(text)
+	}
+
+	var fields []string
+
+	if tok.pattern != nil {
+		// Tokens are whatever the custom pattern matches.
+		fields = tok.pattern.FindAllString(text, -1)
+	} else {
+		// Split at everything that is not a word character.
+		fields = strings.FieldsFunc(text, tok.isSeparator)
+	}
+
+	terms := make([][]byte, len(fields))
+	for i, field := range fields {
+		terms[i] = []byte(field)
+	}
+
+	return terms
+}
+
+// Reports whether the Tokenizer splits documents at r. Unicode aware
+// Tokenizers keep letters, marks and digits of any script, all others
+// only ASCII word characters. Configured word characters are always kept.
+func (tok *Tokenizer) isSeparator(r rune) bool {
+
+	if strings.ContainsRune(tok.wordChars, r) {
+		return false
+	}
+
+	if tok.unicodeAware() {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	}
+
+	return !isWordCharacter(r)
+}
+
+// Reports whether r is an ASCII word character, i.e. a letter,
+// a digit or an underscore. Same as \w in regular expressions.
+func isWordCharacter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_'
+}
+
+// Reports whether Unicode normalization or accent
+// folding were requested for this Tokenizer.
+func (tok *Tokenizer) unicodeAware() bool {
+	return tok.normalize || tok.fold
+}
+
+// Applies the configured Unicode normalization and
+// accent folding to the supplied text.
+func (tok *Tokenizer) normalizeText(text string) string {
+
+	if tok.fold {
+
+		// Decompose, drop combining marks and recompose.
+		folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		if folded, _, err := transform.String(folder, text); err == nil {
+			text = folded
+		}
+	}
+
+	if tok.normalize {
+		text = tok.form.String(text)
+	}
+
+	return text
+}
+
+// Removes stop words from the supplied raw tokens and possibly stems
+// the remaining ones. Also returns the number of tokens present before
+// stop word removal.
+func (tok *Tokenizer) filter(tokens [][]byte) ([]string, int) {
+
+	// Reserve space for result list (tokenized document).
+	resultDocument := make([]string, 0)
+
+	// Range over all produced tokens.
+	for _, token := range tokens {
+
+		term := string(token)
+
+		// Important iteration break: If token is a stop word,
+		// leave current iteration here.
+		if tok.stopWords[term] {
+			continue
+		}
+
+		// Same for tokens too short to be kept.
+		if tok.minLength > 1 && utf8.RuneCountInString(term) < tok.minLength {
+			continue
+		}
+
+		// Alright, token is a new one. Possibly stem and add it to result list.
+		if tok.stem {
+			term = tok.stemmer.Stem(term)
+		}
+		resultDocument = append(resultDocument, term)
+	}
+
+	// Return the tokenized document. Might be of len() = 0.
+	return resultDocument, len(tokens)
+}
+
+// Joins all contiguous sequences of n terms into n-grams.
+func buildNGrams(terms []string, n int) []string {
+
+	ngrams := make([]string, 0)
+
+	for i := 0; i+n <= len(terms); i++ {
+		ngrams = append(ngrams, strings.Join(terms[i:i+n], NGramSeparator))
+	}
+
+	return ngrams
+}
@@ -0,0 +1,261 @@
+package tfidf
+
+import (
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestTokenizerCaseSensitivity(t *testing.T) {
+
+	document := "US policy the WHO"
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"lowercased", NewTokenizer(), []string{"us", "polici"}},
+		{"case kept", NewTokenizer(WithCaseSensitivity(true)), []string{"US", "polici", "WHO"}},
+		{"case kept unstemmed", NewTokenizer(WithCaseSensitivity(true), WithStemming(false)), []string{"US", "policy", "WHO"}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+}
+
+func TestTokenizerCaseSensitivityStopWords(t *testing.T) {
+
+	tok := NewTokenizer(WithCaseSensitivity(true), WithStemming(false))
+
+	// Stop words only match lowercase tokens when case is kept.
+	if got, want := tok.Tokenize("The cat and the dog"), []string{"The", "cat", "dog"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestTokenizerStemTerm(t *testing.T) {
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		term string
+		want string
+	}{
+		{"default", NewTokenizer(), "Running", "run"},
+		{"case kept", NewTokenizer(WithCaseSensitivity(true)), "Running", "Run"},
+		{"case kept acronym", NewTokenizer(WithCaseSensitivity(true)), "WHO", "WHO"},
+		{"unstemmed", NewTokenizer(WithStemming(false)), "Running", "running"},
+		{"custom stemmer", NewTokenizer(WithStemmer(upperStemmer{})), "dogs", "DOGS"},
+		{"accents folded", NewTokenizer(WithAccentFolding(true), WithStemming(false)), "Café", "cafe"},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.StemTerm(test.term); got != test.want {
+			t.Errorf("%s: StemTerm(%q) = %q, want %q", test.name, test.term, got, test.want)
+		}
+	}
+
+	// StemTerm always lowercases, thus only the Tokenizer's own
+	// StemTerm matches tokens of a case-sensitive Tokenizer.
+	tok := NewTokenizer(WithCaseSensitivity(true))
+	document := tok.Tokenize("Run, Forest")
+
+	for _, term := range []string{"Run", "Forest"} {
+
+		if got := TermFrequency(term, true, document, TermWeightingRaw); got != 0.0 {
+			t.Errorf("TermFrequency(%s) with stem flag = %v, want 0", term, got)
+		}
+
+		if got := TermFrequency(tok.StemTerm(term), false, document, TermWeightingRaw); got != 1.0 {
+			t.Errorf("TermFrequency(%s) with Tokenizer.StemTerm = %v, want 1", term, got)
+		}
+	}
+}
+
+func TestTokenizerConcurrentUse(t *testing.T) {
+
+	documents := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"Running runners ran to the running race",
+		"Policies of the policy makers were politically motivated",
+		"Connected connections connecting connectors",
+	}
+
+	tok := NewTokenizer(WithStemCache(8))
+
+	// Sequential results to compare against.
+	want := make([][]string, len(documents))
+	for i, document := range documents {
+		want[i] = NewTokenizer().Tokenize(document)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+
+		wg.Add(1)
+		go func(g int) {
+
+			defer wg.Done()
+
+			for round := 0; round < 50; round++ {
+
+				i := (g + round) % len(documents)
+
+				if got := tok.Tokenize(documents[i]); !reflect.DeepEqual(got, want[i]) {
+					t.Errorf("goroutine %d: Tokenize(%q) = %q, want %q", g, documents[i], got, want[i])
+				}
+
+				if got := TokenizeDocument(documents[i]); !reflect.DeepEqual(got, want[i]) {
+					t.Errorf("goroutine %d: TokenizeDocument(%q) = %q, want %q", g, documents[i], got, want[i])
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
+
+func TestTokenizerStopWords(t *testing.T) {
+
+	document := "The cat sat on the mat"
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"default stop words", NewTokenizer(), []string{"cat", "sat", "mat"}},
+		{"custom stop words", NewTokenizer(WithStopWords([]string{"Cat", "mat"})), []string{"the", "sat", "on", "the"}},
+		{"no stop words", NewTokenizer(WithStopWords([]string{})), []string{"the", "cat", "sat", "on", "the", "mat"}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+
+	// The package level function keeps using the defaults.
+	if got, want := TokenizeDocument(document), []string{"cat", "sat", "mat"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeDocument(%q) = %q, want %q", document, got, want)
+	}
+}
+
+func TestTokenizerNGrams(t *testing.T) {
+
+	document := "I flew to New York City last winter"
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"unigrams", NewTokenizer(WithNGrams(1), WithStemming(false)), []string{"flew", "new", "york", "city", "last", "winter"}},
+		{"bigrams", NewTokenizer(WithNGrams(2), WithStemming(false)), []string{"flew new", "new york", "york city", "city last", "last winter"}},
+		{"trigrams", NewTokenizer(WithNGrams(3), WithStemming(false)), []string{"flew new york", "new york city", "york city last", "city last winter"}},
+		{"stemmed bigrams", NewTokenizer(WithNGrams(2)), []string{"flew new", "new york", "york citi", "citi last", "last winter"}},
+		{"too short", NewTokenizer(WithNGrams(7)), []string{}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+
+	if got, want := TokenizeNGrams(document, 2), tests[3].want; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeNGrams(%q, 2) = %q, want %q", document, got, want)
+	}
+}
+
+func TestTokenizerUnicode(t *testing.T) {
+
+	// The same text, once precomposed and once decomposed.
+	composed := "Café naïve résumé Москва 東京"
+	decomposed := "Cafe\u0301 nai\u0308ve re\u0301sume\u0301 Москва 東京"
+
+	tests := []struct {
+		name     string
+		tok      *Tokenizer
+		document string
+		want     []string
+	}{
+		{"ASCII only", NewTokenizer(WithStemming(false)), "Café naïve", []string{"caf", "na", "ve"}},
+		{"composed", NewTokenizer(WithStemming(false), WithUnicodeNormalization(norm.NFC)), composed, []string{"café", "naïve", "résumé", "москва", "東京"}},
+		{"decomposed", NewTokenizer(WithStemming(false), WithUnicodeNormalization(norm.NFC)), decomposed, []string{"café", "naïve", "résumé", "москва", "東京"}},
+		{"folded", NewTokenizer(WithStemming(false), WithUnicodeNormalization(norm.NFC), WithAccentFolding(true)), decomposed, []string{"cafe", "naive", "resume", "москва", "東京"}},
+		{"folded without normalization", NewTokenizer(WithStemming(false), WithAccentFolding(true)), composed, []string{"cafe", "naive", "resume", "москва", "東京"}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(test.document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, test.document, got, test.want)
+		}
+	}
+}
+
+func TestTokenizerTokenRules(t *testing.T) {
+
+	document := "COVID-19 model-3 v2.0 released."
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"default", NewTokenizer(WithStemming(false)), []string{"covid", "19", "model", "3", "v2", "0", "released"}},
+		{"token pattern", NewTokenizer(WithStemming(false), WithTokenPattern(regexp.MustCompile(`[a-z0-9]+(?:[-.][a-z0-9]+)*`))), []string{"covid-19", "model-3", "v2.0", "released"}},
+		{"word characters", NewTokenizer(WithStemming(false), WithWordCharacters("-.")), []string{"covid-19", "model-3", "v2.0", "released."}},
+		{"pattern over word characters", NewTokenizer(WithStemming(false), WithWordCharacters("-."), WithTokenPattern(regexp.MustCompile(`[a-z]+`))), []string{"covid", "model", "v", "released"}},
+		{"nil pattern", NewTokenizer(WithStemming(false), WithTokenPattern(nil)), []string{"covid", "19", "model", "3", "v2", "0", "released"}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+}
+
+func TestTokenizerMinTokenLength(t *testing.T) {
+
+	document := "I am a big dog"
+
+	tests := []struct {
+		name string
+		tok  *Tokenizer
+		want []string
+	}{
+		{"default", NewTokenizer(WithStopWords([]string{}), WithStemming(false)), []string{"i", "am", "a", "big", "dog"}},
+		{"min length 1", NewTokenizer(WithStopWords([]string{}), WithStemming(false), WithMinTokenLength(1)), []string{"i", "am", "a", "big", "dog"}},
+		{"min length 2", NewTokenizer(WithStopWords([]string{}), WithStemming(false), WithMinTokenLength(2)), []string{"am", "big", "dog"}},
+		{"min length 3", NewTokenizer(WithStopWords([]string{}), WithStemming(false), WithMinTokenLength(3)), []string{"big", "dog"}},
+		{"min length 4", NewTokenizer(WithStopWords([]string{}), WithStemming(false), WithMinTokenLength(4)), []string{}},
+	}
+
+	for _, test := range tests {
+
+		if got := test.tok.Tokenize(document); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", test.name, document, got, test.want)
+		}
+	}
+
+	// Length counts characters, not bytes, of the unstemmed token.
+	tok := NewTokenizer(WithUnicodeNormalization(norm.NFC), WithMinTokenLength(3))
+	if got, want := tok.Tokenize("né runs"), []string{"run"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package tfidf
+
+import (
+	"sort"
+)
+
+// Structs and types
+
+// A term and its tf-idf score.
+type TermScore struct {
+	Term  string
+	Score float64
+}
+
+// Functions
+
+// Extracts the n most distinctive terms of the tokenized doc relative to
+// the corpus, i.e. the terms with the highest tf-idf weight, sorted by
+// descending score. Ties are broken lexicographically by term. If n
+// exceeds the number of distinct terms of doc, all of them are returned,
+// an n <= 0 returns none. Terms of doc outside the corpus vocabulary are
+// not considered.
+func TopTerms(doc []string, documents [][]string, n int, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []TermScore {
+
+	if n < 0 {
+		n = 0
+	}
+
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+
+	return topTerms(tfIdfVector(doc, idfs, tfWeighting), n)
+}
+
+// Returns the n highest scoring terms of the supplied sparse vector,
+// sorted by descending score. Equal scores are ordered lexicographically
+// by term. If n exceeds the number of terms, all terms are returned.
+func topTerms(vector map[string]float64, n int) []TermScore {
+
+	scores := make([]TermScore, 0, len(vector))
+	for term, score := range vector {
+		scores = append(scores, TermScore{Term: term, Score: score})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+
+		return scores[i].Term < scores[j].Term
+	})
+
+	if n >= 0 && n < len(scores) {
+		scores = scores[:n]
+	}
+
+	return scores
+}
+
+// Replaces the tf-idf weights of all terms in the tokenized doc by their
+// rank within the document: the highest scoring term receives rank 1,
+// the next one rank 2 and so on. Equal scores are ranked lexicographically
+// by term. Only terms of doc that are part of the corpus vocabulary are ranked.
+func RankVector(doc []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) map[string]int {
+
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+
+	// Sort all terms of doc by their score.
+	scores := topTerms(tfIdfVector(doc, idfs, tfWeighting), -1)
+
+	ranks := make(map[string]int, len(scores))
+	for i, score := range scores {
+		ranks[score.Term] = i + 1
+	}
+
+	return ranks
+}
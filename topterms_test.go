@@ -0,0 +1,71 @@
+package tfidf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTopTerms(t *testing.T) {
+
+	documents := [][]string{
+		{"go", "go", "go", "rust", "common", "zig", "ada"},
+		{"common", "rust"},
+		{"common", "python"},
+	}
+
+	tests := []struct {
+		n    int
+		want []TermScore
+	}{
+		{0, []TermScore{}},
+		{1, []TermScore{{"go", 3.0 * math.Log(3.0)}}},
+		{3, []TermScore{{"go", 3.0 * math.Log(3.0)}, {"ada", math.Log(3.0)}, {"zig", math.Log(3.0)}}},
+		{10, []TermScore{{"go", 3.0 * math.Log(3.0)}, {"ada", math.Log(3.0)}, {"zig", math.Log(3.0)}, {"rust", math.Log(1.5)}, {"common", 0.0}}},
+	}
+
+	for _, test := range tests {
+
+		got := TopTerms(documents[0], documents, test.n, TermWeightingRaw, InvDocWeightingLog)
+		if len(got) != len(test.want) {
+			t.Fatalf("TopTerms(%d) = %v, want %v", test.n, got, test.want)
+		}
+
+		for i := range got {
+
+			if got[i].Term != test.want[i].Term || !almostEqual(got[i].Score, test.want[i].Score) {
+				t.Errorf("TopTerms(%d)[%d] = %v, want %v", test.n, i, got[i], test.want[i])
+			}
+		}
+	}
+
+	// Terms outside the corpus are not considered.
+	if got := TopTerms([]string{"cobol"}, documents, 5, TermWeightingRaw, InvDocWeightingLog); len(got) != 0 {
+		t.Errorf("TopTerms of unknown term = %v, want none", got)
+	}
+}
+
+func TestRankVector(t *testing.T) {
+
+	documents := [][]string{
+		{"go", "go", "rust", "common", "ada"},
+		{"common", "rust"},
+	}
+
+	// Scores: go = 2 * log(2), ada = log(2), common = rust = 0,
+	// where the tie is ranked lexicographically.
+	want := map[string]int{"go": 1, "ada": 2, "common": 3, "rust": 4}
+
+	if got := RankVector(documents[0], documents, TermWeightingRaw, InvDocWeightingLog); !reflect.DeepEqual(got, want) {
+		t.Errorf("RankVector = %v, want %v", got, want)
+	}
+
+	// Terms outside the corpus vocabulary are not ranked.
+	if got, want := RankVector([]string{"rust", "zig", "go"}, documents, TermWeightingRaw, InvDocWeightingLog), map[string]int{"go": 1, "rust": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RankVector with unknown term = %v, want %v", got, want)
+	}
+
+	if got := RankVector(nil, documents, TermWeightingRaw, InvDocWeightingLog); len(got) != 0 {
+		t.Errorf("RankVector of empty document = %v, want no ranks", got)
+	}
+}
@@ -0,0 +1,207 @@
+package tfidf
+
+import (
+	"math"
+)
+
+// Structs and types
+
+// Combines the term frequency and the inverse document frequency
+// of a term into its final weight, see TfIdfCombined. All other
+// functions of this package, e.g. TfIdfMatrix, the Vectorizer and
+// the scoring functions, always multiply both, see MultiplyTfIdf.
+type CombineFunc func(tf float64, idf float64) float64
+
+// Functions
+
+// The default way of combining term frequency and inverse
+// document frequency: tf * idf.
+func MultiplyTfIdf(tf float64, idf float64) float64 {
+	return tf * idf
+}
+
+// Takes in an already tokenized document and a map of inverse
+// document frequencies and returns the sparse tf-idf vector of
+// the document. Only terms present in both the document and the
+// idf map receive an entry, all other terms implicitly weigh zero.
+func tfIdfVector(document []string, idfs map[string]float64, weighting TermWeighting) map[string]float64 {
+	return combinedVector(document, idfs, weighting, MultiplyTfIdf)
+}
+
+// Works like tfIdfVector but merges term frequency and inverse
+// document frequency of each term with the supplied combine function.
+func combinedVector(document []string, idfs map[string]float64, weighting TermWeighting, combine CombineFunc) map[string]float64 {
+
+	// Initialize result vector.
+	vector := make(map[string]float64)
+
+	counts := termCounts(document)
+	maxFrequency := maxCount(counts)
+
+	// Range over all distinct terms in document.
+	for term, count := range counts {
+
+		if idf, known := idfs[term]; known {
+			vector[term] = combine(weightTermFrequency(count, maxFrequency, weighting), idf)
+		}
+	}
+
+	return vector
+}
+
+// Computes the tf-idf vector of compareDoc relative to the supplied corpus
+// but lets the caller decide how term frequency and inverse document frequency
+// are merged into one weight, e.g. tf + log(idf). A nil combine falls back to
+// MultiplyTfIdf. The result holds an entry for every term of the corpus
+// vocabulary, terms absent from compareDoc are combined with a tf of 0.0.
+func TfIdfCombined(compareDoc []string, documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting, combine CombineFunc) map[string]float64 {
+
+	if combine == nil {
+		combine = MultiplyTfIdf
+	}
+
+	return fullVector(compareDoc, InverseDocumentFrequencies(documents, idfWeighting), tfWeighting, combine)
+}
+
+// Combines tf and idf of every term in the supplied idf map,
+// including those absent from document, into a dense vector.
+func fullVector(document []string, idfs map[string]float64, weighting TermWeighting, combine CombineFunc) map[string]float64 {
+
+	counts := termCounts(document)
+	maxFrequency := maxCount(counts)
+
+	// Combine tf and idf for every term of the vocabulary.
+	vector := make(map[string]float64, len(idfs))
+	for term, idf := range idfs {
+		vector[term] = combine(weightTermFrequency(counts[term], maxFrequency, weighting), idf)
+	}
+
+	return vector
+}
+
+// Computes the sparse tf-idf vectors of all documents in the corpus
+// based on the inverse document frequencies of that same corpus.
+func tfIdfVectors(documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []map[string]float64 {
+
+	// Compute idf only once for the whole corpus.
+	idfs := InverseDocumentFrequencies(documents, idfWeighting)
+
+	vectors := make([]map[string]float64, len(documents))
+
+	for i, document := range documents {
+		vectors[i] = tfIdfVector(document, idfs, tfWeighting)
+	}
+
+	return vectors
+}
+
+// Returns the dot product of two sparse vectors.
+// Terms missing in one of them contribute zero.
+func dotProduct(a map[string]float64, b map[string]float64) float64 {
+
+	// Always range over the smaller vector.
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot float64
+
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+
+	return dot
+}
+
+// Returns the euclidean (L2) norm of a sparse vector.
+func vectorNorm(vector map[string]float64) float64 {
+
+	var sum float64
+
+	for _, weight := range vector {
+		sum += weight * weight
+	}
+
+	return math.Sqrt(sum)
+}
+
+// L2 normalizes a tf-idf vector: returns a copy of vec with each
+// component divided by the vector's euclidean norm, so the result
+// has unit length. vec itself is not modified. If vec is a zero
+// vector, an unchanged copy is returned.
+func Normalize(vec map[string]float64) map[string]float64 {
+
+	unit := make(map[string]float64, len(vec))
+	for term, weight := range vec {
+		unit[term] = weight
+	}
+
+	NormalizeInPlace(unit)
+
+	return unit
+}
+
+// Works like Normalize but modifies the supplied vector in place
+// instead of copying it. A zero vector is left unchanged.
+func NormalizeInPlace(vec map[string]float64) {
+
+	norm := vectorNorm(vec)
+	if norm == 0.0 {
+		return
+	}
+
+	for term, weight := range vec {
+		vec[term] = weight / norm
+	}
+}
+
+// Returns the component-wise mean of all supplied sparse vectors.
+func centroid(vectors []map[string]float64) map[string]float64 {
+
+	mean := make(map[string]float64)
+
+	if len(vectors) == 0 {
+		return mean
+	}
+
+	for _, vector := range vectors {
+		for term, weight := range vector {
+			mean[term] += weight
+		}
+	}
+
+	for term := range mean {
+		mean[term] /= float64(len(vectors))
+	}
+
+	return mean
+}
+
+// Counts the raw number of occurrences of each term in a
+// tokenized document.
+func termCounts(document []string) map[string]float64 {
+
+	counts := make(map[string]float64)
+
+	for _, token := range document {
+		counts[token] += 1.0
+	}
+
+	return counts
+}
+
+// Returns the euclidean (L2) norm of the tf-idf vector of each document
+// in the corpus, in corpus order. Precompute these once for a static corpus
+// and cosine similarity against any query vector q boils down to
+// dot(q, d) / (norm(q) * norms[i]) without renormalizing document vectors.
+func DocumentNorms(documents [][]string, tfWeighting TermWeighting, idfWeighting InvDocWeighting) []float64 {
+
+	vectors := tfIdfVectors(documents, tfWeighting, idfWeighting)
+
+	norms := make([]float64, len(vectors))
+	for i, vector := range vectors {
+		norms[i] = vectorNorm(vector)
+	}
+
+	return norms
+}
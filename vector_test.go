@@ -0,0 +1,135 @@
+package tfidf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+
+	vec := map[string]float64{"a": 3.0, "b": 4.0, "c": 0.0}
+
+	unit := Normalize(vec)
+	if want := map[string]float64{"a": 0.6, "b": 0.8, "c": 0.0}; len(unit) != len(want) {
+		t.Fatalf("Normalize = %v, want %v", unit, want)
+	}
+
+	if !almostEqual(unit["a"], 0.6) || !almostEqual(unit["b"], 0.8) || unit["c"] != 0.0 {
+		t.Errorf("Normalize = %v, want a: 0.6, b: 0.8, c: 0", unit)
+	}
+
+	if norm := vectorNorm(unit); !almostEqual(norm, 1.0) {
+		t.Errorf("norm of normalized vector = %v, want 1", norm)
+	}
+
+	// The input is left untouched.
+	if want := map[string]float64{"a": 3.0, "b": 4.0, "c": 0.0}; !reflect.DeepEqual(vec, want) {
+		t.Errorf("Normalize modified its input to %v", vec)
+	}
+
+	NormalizeInPlace(vec)
+	if !reflect.DeepEqual(vec, unit) {
+		t.Errorf("NormalizeInPlace = %v, want %v", vec, unit)
+	}
+}
+
+func TestNormalizeZeroVector(t *testing.T) {
+
+	for _, vec := range []map[string]float64{{}, {"a": 0.0, "b": 0.0}} {
+
+		want := make(map[string]float64, len(vec))
+		for term, weight := range vec {
+			want[term] = weight
+		}
+
+		if got := Normalize(vec); !reflect.DeepEqual(got, want) {
+			t.Errorf("Normalize(%v) = %v, want %v", vec, got, want)
+		}
+
+		NormalizeInPlace(vec)
+		if !reflect.DeepEqual(vec, want) {
+			t.Errorf("NormalizeInPlace = %v, want %v", vec, want)
+		}
+	}
+
+	if got := Normalize(nil); got == nil || len(got) != 0 {
+		t.Errorf("Normalize(nil) = %#v, want empty map", got)
+	}
+}
+
+func TestTfIdfCombined(t *testing.T) {
+
+	documents := [][]string{
+		{"a", "b", "b"},
+		{"c"},
+	}
+
+	add := func(tf float64, idf float64) float64 {
+		return tf + idf
+	}
+
+	// All terms occur in one of two documents, idf = log(2).
+	want := map[string]float64{
+		"a": 1.0 + math.Log(2.0),
+		"b": 2.0 + math.Log(2.0),
+		"c": math.Log(2.0),
+	}
+
+	got := TfIdfCombined(documents[0], documents, TermWeightingRaw, InvDocWeightingLog, add)
+	if len(got) != len(want) {
+		t.Fatalf("TfIdfCombined = %v, want %v", got, want)
+	}
+
+	for term, weight := range want {
+
+		if !almostEqual(got[term], weight) {
+			t.Errorf("TfIdfCombined[%s] = %v, want %v", term, got[term], weight)
+		}
+	}
+
+	// Multiplying, explicitly or by default, is plain tf-idf.
+	plain := TfIdf(documents[0], documents, TermWeightingRaw, InvDocWeightingLog)
+
+	for _, combine := range []CombineFunc{MultiplyTfIdf, nil} {
+
+		if got := TfIdfCombined(documents[0], documents, TermWeightingRaw, InvDocWeightingLog, combine); !reflect.DeepEqual(got, plain) {
+			t.Errorf("TfIdfCombined = %v, want %v", got, plain)
+		}
+	}
+}
+
+func TestDocumentNorms(t *testing.T) {
+
+	// Idfs: a = log(3 / 2), b = c = log(3).
+	documents := [][]string{
+		{"a", "b", "b"},
+		{"a", "c"},
+		{},
+	}
+
+	common, rare := math.Log(1.5), math.Log(3.0)
+	want := []float64{
+		math.Sqrt(common*common + 4.0*rare*rare),
+		math.Sqrt(common*common + rare*rare),
+		0.0,
+	}
+
+	norms := DocumentNorms(documents, TermWeightingRaw, InvDocWeightingLog)
+	if len(norms) != len(want) {
+		t.Fatalf("got %d norms for %d documents", len(norms), len(want))
+	}
+
+	for i := range want {
+
+		if !almostEqual(norms[i], want[i]) {
+			t.Errorf("norm of document %d = %v, want %v", i, norms[i], want[i])
+		}
+	}
+
+	// Precomputed norms reproduce the cosine similarity.
+	vectors := tfIdfVectors(documents, TermWeightingRaw, InvDocWeightingLog)
+	if got, want := dotProduct(vectors[0], vectors[1])/(norms[0]*norms[1]), CosineSimilarity(vectors[0], vectors[1]); !almostEqual(got, want) {
+		t.Errorf("cosine from norms = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package tfidf
+
+// Structs and types
+
+// Maps tokenized documents to dense tf-idf vectors of fixed length and
+// alignment, e.g. for feeding them into machine learning libraries.
+// Fit learns vocabulary and inverse document frequencies from a corpus,
+// Transform then vectorizes documents against them. Column i of every
+// vector belongs to FeatureNames()[i]. Create one via NewVectorizer.
+type Vectorizer struct {
+	tfWeighting  TermWeighting
+	idfWeighting InvDocWeighting
+	vocabulary   []string
+	index        map[string]int
+	idfs         []float64
+}
+
+// Functions
+
+// Creates a new, unfitted Vectorizer using the supplied
+// term frequency and inverse document frequency weightings.
+func NewVectorizer(tfWeighting TermWeighting, idfWeighting InvDocWeighting) *Vectorizer {
+
+	return &Vectorizer{
+		tfWeighting:  tfWeighting,
+		idfWeighting: idfWeighting,
+		vocabulary:   make([]string, 0),
+		index:        make(map[string]int),
+		idfs:         make([]float64, 0),
+	}
+}
+
+// Learns the sorted vocabulary of the supplied corpus of tokenized
+// documents and the inverse document frequencies of all its terms.
+// Fitting again replaces everything learned before.
+func (v *Vectorizer) Fit(documents [][]string) {
+
+	idfs := InverseDocumentFrequencies(documents, v.idfWeighting)
+
+	v.vocabulary = Vocabulary(documents)
+	v.index = make(map[string]int, len(v.vocabulary))
+	v.idfs = make([]float64, len(v.vocabulary))
+
+	for i, term := range v.vocabulary {
+		v.index[term] = i
+		v.idfs[i] = idfs[term]
+	}
+}
+
+// Returns the dense tf-idf vector of the tokenized doc, aligned to
+// FeatureNames. Terms not part of the fitted vocabulary are ignored,
+// though they still count for the most frequent term of doc the double
+// normalization schemes relate to, exactly like in TfIdf. An unfitted
+// Vectorizer returns an empty vector.
+func (v *Vectorizer) Transform(doc []string) []float64 {
+
+	counts := termCounts(doc)
+	maxFrequency := maxCount(counts)
+
+	vector := make([]float64, len(v.vocabulary))
+	for i, term := range v.vocabulary {
+		vector[i] = weightTermFrequency(counts[term], maxFrequency, v.tfWeighting) * v.idfs[i]
+	}
+
+	return vector
+}
+
+// Fits the Vectorizer to the supplied corpus and returns
+// the transformed vectors of all its documents, in order.
+func (v *Vectorizer) FitTransform(documents [][]string) [][]float64 {
+
+	v.Fit(documents)
+
+	vectors := make([][]float64, len(documents))
+	for i, document := range documents {
+		vectors[i] = v.Transform(document)
+	}
+
+	return vectors
+}
+
+// Returns the fitted vocabulary in column order. The
+// returned slice is a copy and may be modified freely.
+func (v *Vectorizer) FeatureNames() []string {
+
+	names := make([]string, len(v.vocabulary))
+	copy(names, v.vocabulary)
+
+	return names
+}
@@ -0,0 +1,70 @@
+package tfidf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVectorizer(t *testing.T) {
+
+	documents := [][]string{
+		{"cat", "sat", "mat"},
+		{"dog", "sat"},
+		{"cat", "cat", "hat"},
+	}
+
+	v := NewVectorizer(TermWeightingRaw, InvDocWeightingLog)
+
+	// An unfitted Vectorizer yields empty vectors.
+	if got := v.Transform(documents[0]); len(got) != 0 {
+		t.Errorf("unfitted Transform = %v, want empty vector", got)
+	}
+
+	vectors := v.FitTransform(documents)
+
+	names := v.FeatureNames()
+	if want := Vocabulary(documents); !reflect.DeepEqual(names, want) {
+		t.Fatalf("FeatureNames = %q, want %q", names, want)
+	}
+
+	for i, document := range documents {
+
+		if len(vectors[i]) != len(names) {
+			t.Fatalf("vector %d has length %d, want %d", i, len(vectors[i]), len(names))
+		}
+
+		weights := TfIdf(document, documents, TermWeightingRaw, InvDocWeightingLog)
+		for j, name := range names {
+
+			if !almostEqual(vectors[i][j], weights[name]) {
+				t.Errorf("vector %d, column %s = %v, want %v", i, name, vectors[i][j], weights[name])
+			}
+		}
+	}
+
+	// Unknown terms are ignored, vectors keep their length.
+	if got := v.Transform([]string{"bird", "hat"}); len(got) != len(names) {
+		t.Errorf("Transform of unseen terms has length %d, want %d", len(got), len(names))
+	}
+
+	// FeatureNames returns a copy.
+	names[0] = "changed"
+	if v.FeatureNames()[0] == "changed" {
+		t.Errorf("modifying FeatureNames changed the Vectorizer")
+	}
+}
+
+func TestVectorizerRefit(t *testing.T) {
+
+	v := NewVectorizer(TermWeightingBinary, InvDocWeightingUnary)
+	v.Fit([][]string{{"a", "b"}})
+	v.Fit([][]string{{"c"}, {"d"}})
+
+	if got, want := v.FeatureNames(), []string{"c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FeatureNames after refit = %q, want %q", got, want)
+	}
+
+	if got, want := v.Transform([]string{"a", "d"}), []float64{0.0, 1.0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform after refit = %v, want %v", got, want)
+	}
+}